@@ -6,17 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
+	uber "go.uber.org/config"
 	"gopkg.in/yaml.v3"
 
 	"github.com/froppa/stackkit/kits/configkit"
 
 	// Register known modules via init hooks so discovery/check commands
 	// automatically pull in their configuration specs.
+	_ "github.com/froppa/stackkit/kits/appkit"
 	_ "github.com/froppa/stackkit/kits/healthkit"
 	_ "github.com/froppa/stackkit/kits/httpkit"
 	_ "github.com/froppa/stackkit/kits/telemetry"
@@ -57,6 +63,15 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(newConfigCheckCmd())
 	cmd.AddCommand(newConfigListCmd())
 	cmd.AddCommand(newConfigDiscoveryCmd())
+	cmd.AddCommand(newConfigTraceCmd())
+	cmd.AddCommand(newConfigAuditCmd())
+	cmd.AddCommand(newConfigInitCmd())
+	cmd.AddCommand(newConfigSkeletonCmd())
+	cmd.AddCommand(newConfigFingerprintCmd())
+	cmd.AddCommand(newConfigSourcesCmd())
+	cmd.AddCommand(newConfigDiffCmd())
+	cmd.AddCommand(newConfigLintCmd())
+	cmd.AddCommand(newConfigDocsCmd())
 
 	return cmd
 }
@@ -73,6 +88,8 @@ type configCheckOptions struct {
 	key    string
 	all    bool
 	cfgRef string
+	watch  bool
+	format string
 }
 
 func newConfigCheckCmd() *cobra.Command {
@@ -90,6 +107,8 @@ func newConfigCheckCmd() *cobra.Command {
 	flags.StringVar(&opts.key, "key", "", "Configuration key to check (required unless --all is set)")
 	flags.BoolVar(&opts.all, "all", false, "Validate every known configuration key")
 	flags.StringVar(&opts.cfgRef, "config", "", "Path to YAML config file (highest precedence)")
+	flags.BoolVar(&opts.watch, "watch", false, "Re-run the check whenever the resolved config files change")
+	flags.StringVar(&opts.format, "format", "text", "Output format: text|json")
 
 	return cmd
 }
@@ -111,55 +130,191 @@ func runConfigCheck(cmd *cobra.Command, opts *configCheckOptions) error {
 		}
 	}
 
-	provider, err := loadProvider(cmd.Context(), opts.cfgRef)
+	if opts.watch {
+		return watchConfigCheck(cmd, opts, keys)
+	}
+
+	provider, err := configkit.MustLoadForCLI(cmd.Context(), configkit.CLIFlags{Config: opts.cfgRef})
 	if err != nil {
 		return err
 	}
-
-	results := configkit.Check(provider)
-	selected := make(map[string]struct{}, len(keys))
-	for _, k := range keys {
-		selected[k] = struct{}{}
+	write := writeCheckResults
+	if strings.ToLower(opts.format) == "json" {
+		write = writeCheckJSON
+	}
+	exitCode, err := write(cmd.OutOrStdout(), provider, keys)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return &exitError{code: exitCode}
 	}
+	return nil
+}
 
-	out := cmd.OutOrStdout()
+// writeCheckResults prints the check results for keys and returns the exit
+// code they warrant.
+func writeCheckResults(out io.Writer, provider configkit.Provider, keys []string) (int, error) {
 	exitCode := 0
-	for _, r := range results {
-		if _, ok := selected[r.Key]; !ok {
-			continue
-		}
+	for _, r := range selectedResults(provider, keys) {
 		if r.OK {
 			if err := writef(out, "[OK] %s\n", r.Key); err != nil {
-				return err
+				return 0, err
+			}
+			if err := writeDeprecations(out, r); err != nil {
+				return 0, err
 			}
 			continue
 		}
 		for _, issue := range r.Issues {
 			if err := writef(out, "[ERROR] %s: %s\n", formatPath(r.Key, ""), issue); err != nil {
-				return err
+				return 0, err
 			}
 			exitCode = 1
 		}
 		for _, unk := range r.Unknown {
 			if err := writef(out, "[WARN] %s: unknown key %s\n", r.Key, unk); err != nil {
-				return err
+				return 0, err
 			}
 		}
+		if err := writeDeprecations(out, r); err != nil {
+			return 0, err
+		}
 		if r.Err != nil && len(r.Issues) == 0 {
 			if err := writef(out, "[ERROR] %s: %v\n", r.Key, r.Err); err != nil {
-				return err
+				return 0, err
 			}
 			exitCode = 1
 		}
 	}
+	return exitCode, nil
+}
 
-	if exitCode != 0 {
-		return &exitError{code: exitCode}
+// selectedResults checks provider and keeps the results for keys.
+func selectedResults(provider configkit.Provider, keys []string) []configkit.CheckResult {
+	selected := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		selected[k] = struct{}{}
+	}
+	var out []configkit.CheckResult
+	for _, r := range configkit.Check(provider) {
+		if _, ok := selected[r.Key]; ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// checkReport is the JSON form of one configkit.CheckResult.
+type checkReport struct {
+	Key        string                  `json:"key"`
+	Type       string                  `json:"type"`
+	OK         bool                    `json:"ok"`
+	Issues     []string                `json:"issues"`
+	Unknown    []string                `json:"unknown"`
+	Deprecated []configkit.Deprecation `json:"deprecated,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+}
+
+// writeCheckJSON prints the check results for keys as a JSON array and
+// returns the same exit code as writeCheckResults.
+func writeCheckJSON(out io.Writer, provider configkit.Provider, keys []string) (int, error) {
+	exitCode := 0
+	reports := []checkReport{}
+	for _, r := range selectedResults(provider, keys) {
+		rep := checkReport{
+			Key:        r.Key,
+			Type:       r.Type,
+			OK:         r.OK,
+			Issues:     append([]string{}, r.Issues...),
+			Unknown:    append([]string{}, r.Unknown...),
+			Deprecated: r.Deprecated,
+		}
+		if r.Err != nil {
+			rep.Error = r.Err.Error()
+			exitCode = 1
+		}
+		reports = append(reports, rep)
+	}
+	b, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return exitCode, writeln(out, string(b))
+}
+
+func writeDeprecations(out io.Writer, r configkit.CheckResult) error {
+	for _, d := range r.Deprecated {
+		if err := writef(out, "[WARN] %s: %s\n", formatPath(r.Key, d.Path), "deprecated: "+d.Message); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// watchConfigCheck re-runs the check every time one of the resolved config
+// files changes, redrawing the results, until interrupted.
+func watchConfigCheck(cmd *cobra.Command, opts *configCheckOptions, keys []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	flags := configkit.CLIFlags{Config: opts.cfgRef}
+	files := configkit.CLIFiles(flags)
+	for {
+		if err := write(out, "\033[H\033[2J"); err != nil {
+			return err
+		}
+		provider, err := configkit.MustLoadForCLI(ctx, flags)
+		if err != nil {
+			if err := writef(out, "[ERROR] load: %v\n", err); err != nil {
+				return err
+			}
+		} else {
+			// Re-resolve so files created since the last run are picked up.
+			files = mergeFiles(configkit.Files(provider), configkit.CLIFiles(flags))
+			if _, err := writeCheckResults(out, provider, keys); err != nil {
+				return err
+			}
+		}
+		if err := writef(out, "\nwatching %s (Ctrl+C to exit)\n", strings.Join(files, ", ")); err != nil {
+			return err
+		}
+
+		changed, cancel := context.WithCancel(ctx)
+		_ = configkit.WatchFiles(changed, files, configkit.WatchOptions{}, cancel)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func mergeFiles(lists ...[]string) []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, list := range lists {
+		for _, f := range list {
+			if _, ok := seen[f]; ok {
+				continue
+			}
+			seen[f] = struct{}{}
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
 func validateCheckArgs(opts *configCheckOptions) error {
+	switch strings.ToLower(opts.format) {
+	case "text":
+	case "json":
+		if opts.watch {
+			return fmt.Errorf("--watch only supports the text format")
+		}
+	default:
+		return fmt.Errorf("unsupported format %q; use text or json", opts.format)
+	}
 	if opts.all {
 		return nil
 	}
@@ -191,6 +346,7 @@ type configListOptions struct {
 	key         string
 	format      string
 	showSecrets bool
+	redactWords []string
 	cfgRef      string
 }
 
@@ -209,6 +365,7 @@ func newConfigListCmd() *cobra.Command {
 	flags.StringVar(&opts.key, "key", "", "Configuration key to display (required)")
 	flags.StringVar(&opts.format, "format", "yaml", "Output format: yaml|json")
 	flags.BoolVar(&opts.showSecrets, "show-secrets", false, "Include secret values in output")
+	flags.StringArrayVar(&opts.redactWords, "redact-word", nil, "Also mask keys containing this word (repeatable)")
 	flags.StringVar(&opts.cfgRef, "config", "", "Path to YAML config file (highest precedence)")
 
 	return cmd
@@ -219,7 +376,7 @@ func runConfigList(cmd *cobra.Command, opts *configListOptions) error {
 		return fmt.Errorf("--key is required")
 	}
 
-	provider, err := loadProvider(cmd.Context(), opts.cfgRef)
+	provider, err := configkit.MustLoadForCLI(cmd.Context(), configkit.CLIFlags{Config: opts.cfgRef})
 	if err != nil {
 		return err
 	}
@@ -232,7 +389,10 @@ func runConfigList(cmd *cobra.Command, opts *configListOptions) error {
 	if opts.showSecrets {
 		outVal = normalizeForPrint(raw)
 	} else {
-		outVal = configkit.Redact(opts.key, raw)
+		if len(opts.redactWords) > 0 {
+			configkit.SetRedactWords(append(configkit.DefaultRedactWords(), opts.redactWords...)...)
+		}
+		outVal = configkit.Redact(provider, opts.key, raw)
 	}
 
 	out := cmd.OutOrStdout()
@@ -295,7 +455,7 @@ func runConfigDiscovery(cmd *cobra.Command, opts *configDiscoveryOptions) error
 		err      error
 	)
 	if opts.cfgRef != "" {
-		provider, err = configkit.NewYAML(cmd.Context(), configkit.WithSources(configkit.File(opts.cfgRef)))
+		provider, err = configkit.NewYAML(cmd.Context(), configkit.WithFile(opts.cfgRef))
 		if err != nil {
 			return err
 		}
@@ -317,8 +477,14 @@ func runConfigDiscovery(cmd *cobra.Command, opts *configDiscoveryOptions) error
 		}
 		for _, f := range specs {
 			reqMark := ""
+			if len(f.Rules) > 0 {
+				reqMark = " (" + strings.Join(f.Rules, ", ") + ")"
+			}
 			if f.Required {
-				reqMark = " (required)"
+				reqMark += " (required)"
+			}
+			if f.Deprecated != "" {
+				reqMark += " (deprecated: " + f.Deprecated + ")"
 			}
 			if err := writef(out, "    %s: %s%s\n", f.Path, f.Type, reqMark); err != nil {
 				return err
@@ -334,21 +500,625 @@ func runConfigDiscovery(cmd *cobra.Command, opts *configDiscoveryOptions) error
 					return err
 				}
 			}
+			if err := writeDeprecations(out, r); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-// --- helpers --------------------------------------------------------------------
+// --- config trace ---------------------------------------------------------------
+
+type configTraceOptions struct {
+	showSecrets bool
+	cfgRef      string
+}
+
+func newConfigTraceCmd() *cobra.Command {
+	opts := &configTraceOptions{}
+	cmd := &cobra.Command{
+		Use:   "trace <key>",
+		Short: "Show which configuration source set the value of a dotted key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigTrace(cmd, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.showSecrets, "show-secrets", false, "Include secret values in output")
+	flags.StringVar(&opts.cfgRef, "config", "", "Path to YAML config file (highest precedence)")
+	return cmd
+}
+
+func runConfigTrace(cmd *cobra.Command, key string, opts *configTraceOptions) error {
+	provider, err := configkit.MustLoadForCLI(cmd.Context(), configkit.CLIFlags{Config: opts.cfgRef})
+	if err != nil {
+		return err
+	}
+	res, err := configkit.Trace(provider, key)
+	if err != nil {
+		return err
+	}
+
+	show := func(v any) string {
+		if !opts.showSecrets {
+			v = redactLeaf(provider, key, v)
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+
+	out := cmd.OutOrStdout()
+	if !res.Found {
+		if err := writef(out, "%s is not set\n", key); err != nil {
+			return err
+		}
+	} else if err := writef(out, "%s = %s (from %s)\n", key, show(res.Value), res.Source); err != nil {
+		return err
+	}
+	for _, s := range res.Steps {
+		val := "<unset>"
+		if s.Found {
+			val = show(s.Value)
+		}
+		mark := ""
+		if s.Changed {
+			mark = "  *"
+		}
+		if err := writef(out, "  %s: %s%s\n", s.Source, val, mark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactLeaf applies configkit's redaction rules to a value addressed by a
+// dotted key, so the leaf name itself is considered.
+func redactLeaf(p configkit.Provider, key string, v any) any {
+	parent, leaf := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		parent, leaf = key[:i], key[i+1:]
+	}
+	return configkit.Redact(p, parent, map[string]any{leaf: v}).(map[string]any)[leaf]
+}
+
+// --- config audit ---------------------------------------------------------------
+
+func newConfigAuditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "audit [file...]",
+		Short: "Fail if regular config files contain literal secret values",
+		Long: "Scan each file on its own for secret-looking keys (password, token, ...)\n" +
+			"holding literal values instead of ${VAR} placeholders. Without arguments,\n" +
+			"config/config.yml and $CONFIG are scanned. Secrets files are not meant\n" +
+			"to be passed here.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigAudit(cmd, args)
+		},
+	}
+}
+
+func runConfigAudit(cmd *cobra.Command, files []string) error {
+	if len(files) == 0 {
+		// Scan what the default loader would read; no need to build (and
+		// env-expand) a provider for that.
+		for _, f := range configkit.CLIFiles(configkit.CLIFlags{}) {
+			if fi, err := os.Stat(f); err == nil && !fi.IsDir() {
+				files = append(files, f)
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	found := 0
+	for _, f := range files {
+		paths, err := configkit.FindSecretsInSource(configkit.File(f))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		for _, p := range paths {
+			if err := writef(out, "[FAIL] %s: %s holds a literal secret\n", f, p); err != nil {
+				return err
+			}
+		}
+		found += len(paths)
+	}
+	if found > 0 {
+		return &exitError{code: 1}
+	}
+	return writef(out, "[OK] no literal secrets in %d file(s)\n", len(files))
+}
+
+// --- config skeleton ------------------------------------------------------------
+
+func newConfigSkeletonCmd() *cobra.Command {
+	var (
+		key    string
+		all    bool
+		format string
+	)
+	cmd := &cobra.Command{
+		Use:   "skeleton",
+		Short: "Print example YAML or JSON for known configuration keys",
+		Long: "Render an example YAML section for --key, or for every known key with\n" +
+			"--all, one document per key separated by ---. With --format json, --all\n" +
+			"renders a single object with one member per key, and required fields are\n" +
+			"listed in a \"_required\" array next to them.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !all && key == "" {
+				return fmt.Errorf("--key is required unless --all is set")
+			}
+			var asJSON bool
+			switch strings.ToLower(format) {
+			case "", "yaml":
+			case "json":
+				asJSON = true
+			default:
+				return fmt.Errorf("unsupported format %q; use yaml or json", format)
+			}
+			keys, err := collectKeys(key, all)
+			if err != nil {
+				return err
+			}
+			known := make(map[string]configkit.Requirement)
+			for _, r := range configkit.Known() {
+				known[r.Key] = r
+			}
+			out := cmd.OutOrStdout()
+			merged := map[string]json.RawMessage{}
+			for i, k := range keys {
+				req, ok := known[k]
+				if !ok {
+					return fmt.Errorf("unknown configuration key %q", k)
+				}
+				if t, ok := configkit.KnownType(k); ok {
+					configkit.RegisterRequirementType(k, t)
+				}
+				if asJSON {
+					sk, err := configkit.SkeletonJSON(req)
+					if err != nil {
+						return err
+					}
+					if err := json.Unmarshal([]byte(sk), &merged); err != nil {
+						return err
+					}
+					continue
+				}
+				sk, err := configkit.Skeleton(req)
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					if err := writeln(out, "---"); err != nil {
+						return err
+					}
+				}
+				if err := write(out, sk); err != nil {
+					return err
+				}
+			}
+			if !asJSON {
+				return nil
+			}
+			b, err := json.MarshalIndent(merged, "", "  ")
+			if err != nil {
+				return err
+			}
+			return writeln(out, string(b))
+		},
+	}
+	cmd.Flags().StringVar(&key, "key", "", "Configuration key to render (required unless --all is set)")
+	cmd.Flags().BoolVar(&all, "all", false, "Render every known configuration key")
+	cmd.Flags().StringVar(&format, "format", "yaml", "Output format: yaml|json")
+	return cmd
+}
+
+// --- config init ----------------------------------------------------------------
+
+func newConfigInitCmd() *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Write an example config covering every known module",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigInit(cmd, path)
+		},
+	}
+	cmd.Flags().StringVar(&path, "path", filepath.Join("config", "config.yml"), "File to create; never overwritten")
+	return cmd
+}
 
-func loadProvider(ctx context.Context, cfgRef string) (*configkit.YAMLProvider, error) {
-	if cfgRef == "" {
-		return configkit.NewYAML(ctx)
+func runConfigInit(cmd *cobra.Command, path string) error {
+	doc, err := configkit.BootstrapYAML()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists; refusing to overwrite", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, doc); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return writef(cmd.OutOrStdout(), "wrote %s\n", path)
+}
+
+// --- config fingerprint ---------------------------------------------------------
+
+func newConfigFingerprintCmd() *cobra.Command {
+	var cfgRef string
+	cmd := &cobra.Command{
+		Use:   "fingerprint",
+		Short: "Print a stable hash of the resolved, redacted configuration",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			provider, err := configkit.MustLoadForCLI(cmd.Context(), configkit.CLIFlags{Config: cfgRef})
+			if err != nil {
+				return err
+			}
+			fp, err := configkit.Fingerprint(provider)
+			if err != nil {
+				return err
+			}
+			return writeln(cmd.OutOrStdout(), fp)
+		},
+	}
+	cmd.Flags().StringVar(&cfgRef, "config", "", "Path to YAML config file (highest precedence)")
+	return cmd
+}
+
+// --- config sources -------------------------------------------------------------
+
+func newConfigSourcesCmd() *cobra.Command {
+	var cfgRef string
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "List the configuration sources that were loaded, lowest precedence first",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			p, err := configkit.MustLoadForCLI(cmd.Context(), configkit.CLIFlags{Config: cfgRef})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			for i, src := range configkit.LoadedSources(p) {
+				if err := writef(out, "%d. %s\n", i+1, src); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cfgRef, "config", "", "Path to YAML config file (highest precedence)")
+	return cmd
+}
+
+// --- config diff ----------------------------------------------------------------
+
+type configDiffOptions struct {
+	a           string
+	b           string
+	showSecrets bool
+}
+
+func newConfigDiffCmd() *cobra.Command {
+	opts := &configDiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two config files key by key",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigDiff(cmd, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.a, "a", "", "Path to the original config file (required)")
+	flags.StringVar(&opts.b, "b", "", "Path to the changed config file (required)")
+	flags.BoolVar(&opts.showSecrets, "show-secrets", false, "Include secret values in output")
+	return cmd
+}
+
+func runConfigDiff(cmd *cobra.Command, opts *configDiffOptions) error {
+	if opts.a == "" || opts.b == "" {
+		return fmt.Errorf("--a and --b are required")
+	}
+	// Files are compared as written: no default files underneath and no
+	// environment expansion, so ${VAR} placeholders diff literally.
+	from, err := uber.NewYAML(configkit.File(opts.a))
+	if err != nil {
+		return fmt.Errorf("load %s: %w", opts.a, err)
+	}
+	to, err := uber.NewYAML(configkit.File(opts.b))
+	if err != nil {
+		return fmt.Errorf("load %s: %w", opts.b, err)
+	}
+	diff := configkit.Diff
+	if opts.showSecrets {
+		diff = configkit.DiffRaw
+	}
+	changes, err := diff(from, to)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if err := writef(out, "--- %s\n+++ %s\n", opts.a, opts.b); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		var line string
+		switch c.Kind {
+		case configkit.ChangeAdded:
+			line = fmt.Sprintf("+ %s: %s", c.Key, diffValue(c.After))
+		case configkit.ChangeRemoved:
+			line = fmt.Sprintf("- %s: %s", c.Key, diffValue(c.Before))
+		default:
+			line = fmt.Sprintf("~ %s: %s -> %s", c.Key, diffValue(c.Before), diffValue(c.After))
+		}
+		if err := writeln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffValue renders a leaf value as JSON so strings are quoted.
+func diffValue(v any) string {
+	b, err := json.Marshal(normalizeForPrint(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// --- config lint ----------------------------------------------------------------
+
+type configLintOptions struct {
+	format string
+	cfgRef string
+}
+
+// lintFinding is one problem reported by config lint.
+type lintFinding struct {
+	Severity string `json:"severity"` // "error" or "warning"
+	Category string `json:"category"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+// lintCategories fixes the order of the text report.
+var lintCategories = []string{"load", "missing_env", "secrets", "validation", "unknown_keys", "deprecated"}
+
+func newConfigLintCmd() *cobra.Command {
+	opts := &configLintOptions{}
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Run every config check (validation, unknown keys, env, secrets) in one report",
+		Long: "Validate every known key against the resolved config, report unknown\n" +
+			"and deprecated keys, ${VAR} placeholders without a default whose\n" +
+			"variable is unset, and literal secrets in config files. Exits 1 if any\n" +
+			"error-severity finding exists; warnings alone exit 0.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConfigLint(cmd, opts)
+		},
 	}
-	return configkit.NewYAML(ctx, configkit.WithSources(configkit.File(cfgRef)))
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "text", "Output format: text|json")
+	flags.StringVar(&opts.cfgRef, "config", "", "Path to YAML config file (highest precedence)")
+	return cmd
+}
+
+func runConfigLint(cmd *cobra.Command, opts *configLintOptions) error {
+	format := strings.ToLower(opts.format)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format %q; use text or json", opts.format)
+	}
+	findings, err := lintConfig(cmd.Context(), configkit.CLIFlags{Config: opts.cfgRef})
+	if err != nil {
+		return err
+	}
+
+	errs, warns := 0, 0
+	for _, f := range findings {
+		if f.Severity == "error" {
+			errs++
+		} else {
+			warns++
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if format == "json" {
+		if findings == nil {
+			findings = []lintFinding{}
+		}
+		b, err := json.MarshalIndent(map[string]any{
+			"findings": findings,
+			"errors":   errs,
+			"warnings": warns,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeln(out, string(b)); err != nil {
+			return err
+		}
+	} else if err := writeLintReport(out, findings, errs, warns); err != nil {
+		return err
+	}
+	if errs > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// lintConfig collects findings for the files resolved from flags. Files that
+// do not parse and missing environment variables make loading fail, so
+// validation is skipped only then; literal secrets do not stop it.
+func lintConfig(ctx context.Context, flags configkit.CLIFlags) ([]lintFinding, error) {
+	var findings []lintFinding
+	loadable := true
+	for _, f := range configkit.CLIFiles(flags) {
+		if fi, err := os.Stat(f); err != nil || fi.IsDir() {
+			continue
+		}
+		missing, err := configkit.FindMissingEnvInSource(configkit.File(f), nil)
+		if err != nil {
+			findings = append(findings, lintFinding{Severity: "error", Category: "load", Path: f, Message: err.Error()})
+			loadable = false
+			continue
+		}
+		if len(missing) > 0 {
+			loadable = false
+		}
+		for _, m := range missing {
+			findings = append(findings, lintFinding{
+				Severity: "error", Category: "missing_env", Path: m.Path,
+				Message: fmt.Sprintf("%s: ${%s} is unset and has no default", f, m.Var),
+			})
+		}
+		secrets, err := configkit.FindSecretsInSource(configkit.File(f))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		for _, p := range secrets {
+			findings = append(findings, lintFinding{
+				Severity: "error", Category: "secrets", Path: p,
+				Message: f + ": literal secret; use ${VAR} or a secrets file",
+			})
+		}
+	}
+	if !loadable {
+		return findings, nil
+	}
+
+	provider, err := configkit.MustLoadForCLI(ctx, flags)
+	if err != nil {
+		return append(findings, lintFinding{Severity: "error", Category: "load", Message: err.Error()}), nil
+	}
+	for _, r := range configkit.Known() {
+		if t, ok := configkit.KnownType(r.Key); ok {
+			configkit.RegisterRequirementType(r.Key, t)
+		}
+	}
+	for _, r := range configkit.Check(provider) {
+		for _, issue := range r.Issues {
+			findings = append(findings, lintFinding{Severity: "error", Category: "validation", Path: r.Key, Message: issue})
+		}
+		if r.Err != nil && len(r.Issues) == 0 {
+			findings = append(findings, lintFinding{Severity: "error", Category: "validation", Path: r.Key, Message: r.Err.Error()})
+		}
+		for _, unk := range r.Unknown {
+			findings = append(findings, lintFinding{Severity: "warning", Category: "unknown_keys", Path: r.Key, Message: "unknown key " + unk})
+		}
+		for _, d := range r.Deprecated {
+			findings = append(findings, lintFinding{Severity: "warning", Category: "deprecated", Path: formatPath(r.Key, d.Path), Message: d.Message})
+		}
+	}
+	return findings, nil
+}
+
+func writeLintReport(out io.Writer, findings []lintFinding, errs, warns int) error {
+	for _, cat := range lintCategories {
+		header := false
+		for _, f := range findings {
+			if f.Category != cat {
+				continue
+			}
+			if !header {
+				if err := writeln(out, cat); err != nil {
+					return err
+				}
+				header = true
+			}
+			label := "[WARN]"
+			if f.Severity == "error" {
+				label = "[ERROR]"
+			}
+			line := "  " + label + " "
+			if f.Path != "" {
+				line += f.Path + ": "
+			}
+			if err := writeln(out, line+f.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return writef(out, "%d error(s), %d warning(s)\n", errs, warns)
+}
+
+// --- config docs ----------------------------------------------------------------
+
+func newConfigDocsCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Export every known config key and its fields as JSON or Markdown",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			switch strings.ToLower(format) {
+			case "json":
+				b, err := configkit.ExportDocs()
+				if err != nil {
+					return err
+				}
+				return writeln(cmd.OutOrStdout(), string(b))
+			case "markdown":
+				return write(cmd.OutOrStdout(), renderDocsMarkdown(configkit.Docs()))
+			default:
+				return fmt.Errorf("unsupported format %q; use json or markdown", format)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "json", "Output format: json|markdown")
+	return cmd
+}
+
+// renderDocsMarkdown renders one table per module.
+func renderDocsMarkdown(docs []configkit.ModuleDoc) string {
+	var b strings.Builder
+	b.WriteString("# Configuration reference\n")
+	for _, d := range docs {
+		key := d.Key
+		if key == "" {
+			key = "(root)"
+		}
+		fmt.Fprintf(&b, "\n## `%s` (%s)\n\n", key, d.Type)
+		if len(d.Fields) == 0 {
+			b.WriteString("No fields.\n")
+			continue
+		}
+		b.WriteString("| Key | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+		for _, f := range d.Fields {
+			required := ""
+			if f.Required {
+				required = "yes"
+			}
+			desc := f.Description
+			if f.Deprecated != "" {
+				desc = strings.TrimSpace("**Deprecated:** " + f.Deprecated + " " + desc)
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n",
+				formatPath(d.Key, f.Path), f.Type, required, strings.ReplaceAll(desc, "|", "\\|"))
+		}
+	}
+	return b.String()
 }
 
+// --- helpers --------------------------------------------------------------------
+
 func formatPath(key, path string) string {
 	if path == "" {
 		return key
@@ -29,7 +29,7 @@ func main() {
 		fx.WithLogger(func(log *zap.Logger) fxevent.Logger {
 			return fxeventlog.NewMinimal(log)
 		}),
-		fx.Supply(context.Background()),
+		fx.Provide(context.Background),
 		configkit.Module(
 			configkit.WithEmbeddedBytes([]byte(embeddedConfig)),
 		),
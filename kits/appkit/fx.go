@@ -0,0 +1,56 @@
+// Package appkit provides app-wide Fx settings driven by configuration.
+//
+// Fx options such as fx.StartTimeout must be known before the container is
+// built, so appkit reads its config directly via configkit.Load rather than
+// through the container.
+package appkit
+
+import (
+	"time"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"go.uber.org/fx"
+)
+
+func init() { configkit.RegisterKnown("app", (*Config)(nil)) }
+
+// Config bounds application startup and shutdown.
+type Config struct {
+	// StartTimeout bounds how long all OnStart hooks may take. Zero keeps
+	// Fx's default (15s).
+	StartTimeout time.Duration `yaml:"start_timeout" validate:"gte=0"`
+
+	// StopTimeout bounds how long all OnStop hooks may take. Zero keeps
+	// Fx's default (15s). Keep it above shutdownkit's graceful timeout.
+	StopTimeout time.Duration `yaml:"stop_timeout" validate:"gte=0"`
+}
+
+// Timeouts loads the "app" config with the same sources as configkit.Module
+// and returns fx.StartTimeout/fx.StopTimeout for the values that are set.
+// Pass the same options given to configkit.Module. Config errors surface as
+// an fx.Error when the app is built.
+//
+// The timeouts apply to app.Run(); callers using app.Start/Stop directly
+// bound them with their own contexts.
+func Timeouts(opts ...configkit.ModuleOption) fx.Option {
+	p, err := configkit.Load(opts...)
+	if err != nil {
+		return fx.Error(err)
+	}
+	cfg, err := configkit.PopulateWithDefaults(p, "app", Config{})
+	if err != nil {
+		return fx.Error(err)
+	}
+	return options(*cfg)
+}
+
+func options(cfg Config) fx.Option {
+	var out []fx.Option
+	if cfg.StartTimeout > 0 {
+		out = append(out, fx.StartTimeout(cfg.StartTimeout))
+	}
+	if cfg.StopTimeout > 0 {
+		out = append(out, fx.StopTimeout(cfg.StopTimeout))
+	}
+	return fx.Options(out...)
+}
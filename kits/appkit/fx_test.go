@@ -0,0 +1,63 @@
+package appkit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/froppa/stackkit/kits/appkit"
+	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestTimeouts_FromConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := fx.New(
+		fx.NopLogger,
+		appkit.Timeouts(configkit.WithEmbeddedBytes([]byte("app:\n  start_timeout: 1500ms\n  stop_timeout: 3s\n"))),
+	)
+	require.NoError(t, app.Err())
+	require.Equal(t, 1500*time.Millisecond, app.StartTimeout())
+	require.Equal(t, 3*time.Second, app.StopTimeout())
+}
+
+func TestTimeouts_DefaultsWhenUnset(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := fx.New(fx.NopLogger, appkit.Timeouts())
+	require.NoError(t, app.Err())
+	require.Equal(t, fx.DefaultTimeout, app.StartTimeout())
+	require.Equal(t, fx.DefaultTimeout, app.StopTimeout())
+}
+
+func TestTimeouts_InvalidConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := fx.New(
+		fx.NopLogger,
+		appkit.Timeouts(configkit.WithEmbeddedBytes([]byte("app:\n  start_timeout: -1s\n"))),
+	)
+	require.Error(t, app.Err())
+	require.True(t, strings.Contains(app.Err().Error(), "validation failed"), app.Err().Error())
+}
+
+func TestTimeouts_BoundsHungStart(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := fx.New(
+		fx.NopLogger,
+		appkit.Timeouts(configkit.WithEmbeddedBytes([]byte("app:\n  start_timeout: 50ms\n"))),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{OnStart: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}})
+		}),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	require.ErrorIs(t, app.Start(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,92 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+// Anchors and aliases are resolved within each source before sources are
+// layered. These cases pin down what that means for overrides.
+func TestAnchors_ResolvedPerSource(t *testing.T) {
+	const base = "defaults: &defaults\n  timeout: 1s\n  retries: 2\n" +
+		"db:\n  <<: *defaults\n  pool: 5\n" +
+		"cache: *defaults\n"
+
+	tests := []struct {
+		name     string
+		override string
+		want     map[string]any
+		wantErr  string
+	}{
+		{
+			name: "alias and merge key within one source",
+			want: map[string]any{
+				"db":    map[string]any{"timeout": "1s", "retries": 2, "pool": 5},
+				"cache": map[string]any{"timeout": "1s", "retries": 2},
+			},
+		},
+		{
+			name:     "overriding the anchored node does not touch aliases",
+			override: "defaults:\n  timeout: 9s\n",
+			want: map[string]any{
+				"defaults": map[string]any{"timeout": "9s", "retries": 2},
+				"db":       map[string]any{"timeout": "1s", "retries": 2, "pool": 5},
+				"cache":    map[string]any{"timeout": "1s", "retries": 2},
+			},
+		},
+		{
+			name:     "overriding an alias target merges like any value",
+			override: "cache:\n  retries: 7\n",
+			want: map[string]any{
+				"cache": map[string]any{"timeout": "1s", "retries": 7},
+			},
+		},
+		{
+			name:     "anchors defined in the overriding source work there",
+			override: "fast: &fast\n  timeout: 10ms\ncache: *fast\n",
+			want: map[string]any{
+				"cache": map[string]any{"timeout": "10ms", "retries": 2},
+			},
+		},
+		{
+			name:     "alias to an anchor from another source is an error",
+			override: "cache: *defaults\n",
+			wantErr:  "aliases cannot reference anchors defined in another source",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			cwd, _ := os.Getwd()
+			_ = os.Chdir(tmp)
+			t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+			writeFile(t, filepath.Join("config", "config.yml"), []byte(base))
+			var opts []config.ModuleOption
+			override := filepath.Join(tmp, "override.yml")
+			if tt.override != "" {
+				writeFile(t, override, []byte(tt.override))
+				opts = append(opts, config.WithFile(override))
+			}
+
+			p, err := config.NewYAML(context.Background(), opts...)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				require.ErrorContains(t, err, override, "error should name the offending source")
+				return
+			}
+			require.NoError(t, err)
+			for key, want := range tt.want {
+				got, err := config.GetMap(p, key)
+				require.NoError(t, err)
+				require.Equal(t, want, got, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	uber "go.uber.org/config"
+)
+
+// FindSecretsInSource returns the dotted paths in src (e.g. File(path)) whose key looks like a
+// secret (see Redact) and whose value holds literal text rather than being
+// empty or made only of `${VAR}` placeholders; a `${VAR:default}` default
+// counts as literal. Use it on regular config files to catch credentials that
+// belong in a secrets file or the environment.
+//
+// Only src itself is inspected; placeholders are not expanded. Non-string
+// values (e.g. `token_ttl: 30`) are not reported.
+func FindSecretsInSource(src Source) ([]string, error) {
+	p, err := uber.NewYAML(src)
+	if err != nil {
+		return nil, fmt.Errorf("config: audit: %w", err)
+	}
+	var raw any
+	if err := p.Get(uber.Root).Populate(&raw); err != nil {
+		return nil, fmt.Errorf("config: audit: %w", err)
+	}
+	var out []string
+	findSecrets("", normalize(raw), &out)
+	sort.Strings(out)
+	return out, nil
+}
+
+func findSecrets(prefix string, v any, out *[]string) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	for k, val := range m {
+		path := joinKey(prefix, k)
+		if _, nested := val.(map[string]any); nested {
+			findSecrets(path, val, out)
+			continue
+		}
+		if isSecretKey(k) && hasLiteral(val) {
+			*out = append(*out, path)
+		}
+	}
+}
+
+// hasLiteral reports whether v holds a string with literal text, directly or
+// as a list element. Only `${VAR}` placeholders without a default count as
+// not literal: text around them, or a `${VAR:default}` default, does.
+func hasLiteral(v any) bool {
+	switch t := v.(type) {
+	case string:
+		rest := t
+		for _, m := range placeholder.FindAllStringSubmatchIndex(t, -1) {
+			if m[5]-m[4] > 1 { // non-empty ":default"
+				return true
+			}
+			rest = strings.Replace(rest, t[m[0]:m[1]], "", 1)
+		}
+		return strings.TrimSpace(rest) != ""
+	case []any:
+		for _, e := range t {
+			if hasLiteral(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MissingEnv is a `${VAR}` placeholder without a default whose variable is
+// not set.
+type MissingEnv struct {
+	Path string // dotted path of the value holding the placeholder
+	Var  string
+}
+
+var placeholder = regexp.MustCompile(`\$\{([^}:]+)(:[^}]*)?\}`)
+
+// FindMissingEnvInSource returns the placeholders in src that would make env
+// expansion fail: no default, and lookup (os.LookupEnv when nil) reports the
+// variable as unset. Results are sorted by path.
+func FindMissingEnvInSource(src Source, lookup func(string) (string, bool)) ([]MissingEnv, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	p, err := uber.NewYAML(src)
+	if err != nil {
+		return nil, fmt.Errorf("config: audit: %w", err)
+	}
+	var raw any
+	if err := p.Get(uber.Root).Populate(&raw); err != nil {
+		return nil, fmt.Errorf("config: audit: %w", err)
+	}
+	var out []MissingEnv
+	findMissingEnv("", normalize(raw), lookup, &out)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Var < out[j].Var
+	})
+	return out, nil
+}
+
+func findMissingEnv(path string, v any, lookup func(string) (string, bool), out *[]MissingEnv) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			findMissingEnv(joinKey(path, k), val, lookup, out)
+		}
+	case []any:
+		for _, val := range t {
+			findMissingEnv(path, val, lookup, out)
+		}
+	case string:
+		for _, m := range placeholder.FindAllStringSubmatch(t, -1) {
+			if m[2] != "" {
+				continue // has a default
+			}
+			if _, ok := lookup(m[1]); !ok {
+				*out = append(*out, MissingEnv{Path: path, Var: m[1]})
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package configkit_test
+
+import (
+	"bytes"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	uber "go.uber.org/config"
+)
+
+func TestFindSecretsInSource(t *testing.T) {
+	src := uber.Source(bytes.NewBufferString(`
+db:
+  host: localhost
+  password: hunter2
+  dsn: ${DB_DSN}
+  token_ttl: 30
+api:
+  key: ""
+  tokens: [abc, "${T2}"]
+  secret: ${API_SECRET:fallback}
+oauth:
+  client_secret: "  "
+  bearer: "${A}x${B}"
+`))
+	found, err := config.FindSecretsInSource(src)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api.secret", "api.tokens", "db.password", "oauth.bearer"}, found)
+}
+
+func TestFindSecretsInSource_InvalidYAML(t *testing.T) {
+	_, err := config.FindSecretsInSource(uber.Source(bytes.NewBufferString("a: [\n")))
+	require.Error(t, err)
+}
+
+func TestFindMissingEnvInSource(t *testing.T) {
+	src := uber.Source(bytes.NewBufferString(`
+db:
+  dsn: ${DB_DSN}
+  host: ${DB_HOST:localhost}
+  user: ${DB_USER}
+hosts: ["${H1}", "x-${H2}"]
+`))
+	env := map[string]string{"DB_USER": "svc", "H1": "a"}
+	missing, err := config.FindMissingEnvInSource(src, func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	})
+	require.NoError(t, err)
+	require.Equal(t, []config.MissingEnv{
+		{Path: "db.dsn", Var: "DB_DSN"},
+		{Path: "hosts", Var: "H2"},
+	}, missing)
+}
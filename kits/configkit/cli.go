@@ -0,0 +1,47 @@
+package configkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// CLIFlags holds the config-related flag values of a command-line tool.
+type CLIFlags struct {
+	// Config is the --config file. It has the highest precedence and must
+	// exist when set.
+	Config string
+}
+
+// MustLoadForCLI builds a provider with the precedence shared by all CLIs:
+//
+//	config/config.toml, config/config.json, config/config.yml (if present) -> $CONFIG (must exist if set) -> flags.Config (must exist if set)
+//
+// Environment expansion is always applied. Errors name the source that failed:
+// the flag or variable pointing at a missing file, or the file that did not
+// parse. Despite the name it does not panic; "must" refers to the sources that
+// are required to exist.
+func MustLoadForCLI(ctx context.Context, flags CLIFlags) (*YAMLProvider, error) {
+	var opts []ModuleOption
+	if flags.Config != "" {
+		if !isFile(flags.Config) {
+			return nil, fmt.Errorf("config: --config path %q not found or not a file", flags.Config)
+		}
+		opts = append(opts, WithFile(flags.Config))
+	}
+	return NewYAML(ctx, opts...)
+}
+
+// CLIFiles lists the files MustLoadForCLI may read for flags, lowest
+// precedence first, whether or not they exist yet. Watchers use it so that
+// creating a missing file also counts as a change.
+func CLIFiles(flags CLIFlags) []string {
+	files := configFiles("config", "config")
+	if p, ok := os.LookupEnv("CONFIG"); ok {
+		files = append(files, p)
+	}
+	if flags.Config != "" {
+		files = append(files, flags.Config)
+	}
+	return files
+}
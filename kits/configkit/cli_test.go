@@ -0,0 +1,54 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustLoadForCLI_Precedence(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("a: default\nb: default\nc: default\n"))
+	envFile := filepath.Join(tmp, "env.yml")
+	writeFile(t, envFile, []byte("b: env\nc: env\n"))
+	t.Setenv("CONFIG", envFile)
+	cliFile := filepath.Join(tmp, "cli.yml")
+	writeFile(t, cliFile, []byte("c: flag\n"))
+
+	flags := config.CLIFlags{Config: cliFile}
+	p, err := config.MustLoadForCLI(context.Background(), flags)
+	require.NoError(t, err)
+	var out map[string]string
+	require.NoError(t, p.Get("").Populate(&out))
+	require.Equal(t, map[string]string{"a": "default", "b": "env", "c": "flag"}, out)
+	require.Equal(t, []string{filepath.Join("config", "config.toml"), filepath.Join("config", "config.json"), filepath.Join("config", "config.yml"), envFile, cliFile}, config.CLIFiles(flags))
+}
+
+func TestMustLoadForCLI_Errors(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	missing := filepath.Join(tmp, "missing.yml")
+	_, err := config.MustLoadForCLI(context.Background(), config.CLIFlags{Config: missing})
+	require.ErrorContains(t, err, `--config path "`+missing+`" not found`)
+
+	t.Setenv("CONFIG", missing)
+	_, err = config.MustLoadForCLI(context.Background(), config.CLIFlags{})
+	require.ErrorContains(t, err, `CONFIG path "`+missing+`" not found`)
+
+	bad := filepath.Join(tmp, "bad.yml")
+	writeFile(t, bad, []byte("a: [\n"))
+	t.Setenv("CONFIG", bad)
+	_, err = config.MustLoadForCLI(context.Background(), config.CLIFlags{})
+	require.ErrorContains(t, err, `source "`+bad+`"`)
+}
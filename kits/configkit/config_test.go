@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -171,6 +172,46 @@ func TestModule_DefaultConfigDir(t *testing.T) {
 	assert.Equal(t, "hello", out.Foo)
 }
 
+func TestModule_JSONConfigFiles(t *testing.T) {
+	configkit.ResetDiscoveryForTests()
+	tmp := t.TempDir()
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmp))
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	require.NoError(t, writeConfigFile(t, filepath.Join("config", "config.json"),
+		[]byte("{\n\t\"app\": {\"name\": \"json\", \"port\": 8080, \"mode\": \"json\"}\n}\n")))
+	require.NoError(t, writeConfigFile(t, filepath.Join("config", "config.yml"), []byte("app:\n  mode: yaml\n")))
+	require.NoError(t, writeConfigFile(t, filepath.Join("config", "config.local.json"), []byte(`{"app": {"name": "local"}}`)))
+
+	type appCfg struct {
+		Name string `yaml:"name"`
+		Port int    `yaml:"port"`
+		Mode string `yaml:"mode"`
+	}
+
+	var (
+		out appCfg
+		p   configkit.Provider
+	)
+	startApp(t,
+		configkit.Module(),
+		fx.Provide(configkit.ProvideFromKey[appCfg]("app")),
+		fx.Invoke(func(c *appCfg, prov configkit.Provider) { out, p = *c, prov }),
+	)
+
+	// YAML wins ties with its JSON twin; config.local.json beats both.
+	assert.Equal(t, appCfg{Name: "local", Port: 8080, Mode: "yaml"}, out)
+
+	// Unknown keys are still detected in JSON sources.
+	type nameOnly struct {
+		Name string `yaml:"name"`
+	}
+	res := configkit.CheckType(p, "app", reflect.TypeOf(nameOnly{}))
+	assert.Equal(t, []string{"mode", "port"}, res.Unknown)
+}
+
 func TestModule_WithSources_Precedence(t *testing.T) {
 	tmp := t.TempDir()
 	cwd, err := os.Getwd()
@@ -225,3 +266,80 @@ func TestEnvExpansion_Overrides(t *testing.T) {
 
 	assert.Equal(t, ":9999", out.HTTP.Addr)
 }
+
+func TestPopulateWithDefaults(t *testing.T) {
+	type Pool struct {
+		Max  int `yaml:"max" validate:"min=1"`
+		Idle int `yaml:"idle"`
+	}
+	type DB struct {
+		Host string `yaml:"host" validate:"required"`
+		Port int    `yaml:"port"`
+		Pool Pool   `yaml:"pool"`
+	}
+	defaults := DB{Host: "localhost", Port: 5432, Pool: Pool{Max: 10, Idle: 2}}
+
+	p, err := configFile(t, []byte("db:\n  port: 6543\n  pool:\n    max: 20\n"))
+	require.NoError(t, err)
+
+	got, err := configkit.PopulateWithDefaults(p, "db", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, DB{Host: "localhost", Port: 6543, Pool: Pool{Max: 20, Idle: 2}}, *got)
+	assert.Equal(t, 10, defaults.Pool.Max, "defaults must not be mutated")
+
+	got, err = configkit.PopulateWithDefaults(p, "missing", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, defaults, *got)
+
+	bad, err := configFile(t, []byte("db:\n  pool:\n    max: 0\n"))
+	require.NoError(t, err)
+	_, err = configkit.PopulateWithDefaults(bad, "db", defaults)
+	require.ErrorContains(t, err, "validation failed")
+}
+
+func TestPopulateWithDefaults_SharedMapDefaults(t *testing.T) {
+	type Upstream struct {
+		Headers map[string]string `yaml:"headers"`
+		Retry   *struct {
+			Max int `yaml:"max"`
+		} `yaml:"retry"`
+	}
+	defaults := Upstream{Headers: map[string]string{"X-Base": "1"}}
+	defaults.Retry = &struct {
+		Max int `yaml:"max"`
+	}{Max: 3}
+
+	first, err := configFile(t, []byte("up:\n  headers:\n    X-Extra: \"2\"\n  retry:\n    max: 5\n"))
+	require.NoError(t, err)
+	got, err := configkit.PopulateWithDefaults(first, "up", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Base": "1", "X-Extra": "2"}, got.Headers)
+	assert.Equal(t, 5, got.Retry.Max)
+
+	second, err := configFile(t, []byte("other: true\n"))
+	require.NoError(t, err)
+	got, err = configkit.PopulateWithDefaults(second, "up", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Base": "1"}, got.Headers, "the first call must not leak into the second")
+	assert.Equal(t, 3, got.Retry.Max)
+	assert.Equal(t, map[string]string{"X-Base": "1"}, defaults.Headers)
+	assert.Equal(t, 3, defaults.Retry.Max)
+}
+
+func TestGetMap(t *testing.T) {
+	p, err := configFile(t, []byte("flags:\n  beta: true\n  limits:\n    rps: 10\n  tags: [a, b]\nname: svc\n"))
+	require.NoError(t, err)
+
+	m, err := configkit.GetMap(p, "flags")
+	require.NoError(t, err)
+	assert.Equal(t, true, m["beta"])
+	assert.Equal(t, map[string]any{"rps": 10}, m["limits"])
+	assert.Equal(t, []any{"a", "b"}, m["tags"])
+
+	m, err = configkit.GetMap(p, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, m)
+
+	_, err = configkit.GetMap(p, "name")
+	require.ErrorContains(t, err, "not a map")
+}
@@ -0,0 +1,162 @@
+package configkit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// applyDefaults sets every field of v, at any depth, that carries a
+// `default:"..."` tag and whose key is absent from raw, the YAML subtree v was
+// populated from, to the parsed tag value. A key set explicitly, even to
+// false, 0, or 0s, keeps its value. Strings, bools, integers, floats, and
+// time.Duration (written as "5s") are supported.
+func applyDefaults(v reflect.Value, path string, raw any) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return applyDefaults(v.Elem(), path, raw)
+		}
+	case reflect.Struct:
+		m, _ := raw.(map[string]any)
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, inline := parseYAMLTag(f.Tag.Get("yaml"), f)
+			fpath, sub := path, raw
+			if !inline {
+				fpath, sub = joinKey(path, name), m[name]
+			}
+			fv := v.Field(i)
+			// A Go value default (PopulateWithDefaults) also wins over the tag.
+			if def, ok := f.Tag.Lookup("default"); ok && sub == nil && fv.IsZero() {
+				if err := setDefault(fv, def); err != nil {
+					return fmt.Errorf("config: invalid default for %s: %w", fpath, err)
+				}
+				continue
+			}
+			if err := applyDefaults(fv, fpath, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rawSubtree returns the normalized subtree at key, or nil when it is unset.
+func rawSubtree(p Provider, key string) any {
+	var raw any
+	if err := p.Get(key).Populate(&raw); err != nil {
+		return nil
+	}
+	return normalize(raw)
+}
+
+// setDefault parses s into v according to v's kind.
+func setDefault(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// deepCopy returns a copy of v that shares no maps, slices, or pointers with
+// it, so decoding into the copy cannot write through to v. Unexported struct
+// fields are copied as they are.
+func deepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src)
+	return dst.Interface().(T)
+}
+
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		copyValue(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		e := reflect.New(src.Elem().Type()).Elem()
+		copyValue(e, src.Elem())
+		dst.Set(e)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		for it := src.MapRange(); it.Next(); {
+			e := reflect.New(src.Type().Elem()).Elem()
+			copyValue(e, it.Value())
+			m.SetMapIndex(it.Key(), e)
+		}
+		dst.Set(m)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := range src.Len() {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Struct:
+		dst.Set(src)
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
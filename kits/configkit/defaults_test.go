@@ -0,0 +1,47 @@
+package configkit_test
+
+import (
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultedCfg struct {
+	Addr    string        `yaml:"addr" default:":8080" validate:"required"`
+	Timeout time.Duration `yaml:"timeout" default:"5s"`
+	Retries int           `yaml:"retries" default:"3"`
+	Ratio   float64       `yaml:"ratio" default:"0.5"`
+	Enabled bool          `yaml:"enabled" default:"true"`
+	TLS     struct {
+		Port uint16 `yaml:"port" default:"8443"`
+	} `yaml:"tls"`
+}
+
+func TestProvideFromKey_DefaultTags(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	p := providerFromYAML(t, "srv:\n  retries: 7\n")
+
+	cfg, err := config.ProvideFromKey[defaultedCfg]("srv")(p)
+	require.NoError(t, err)
+	require.Equal(t, ":8080", cfg.Addr)
+	require.Equal(t, 5*time.Second, cfg.Timeout)
+	require.Equal(t, 7, cfg.Retries, "set values win over defaults")
+	require.Equal(t, 0.5, cfg.Ratio)
+	require.True(t, cfg.Enabled)
+	require.Equal(t, uint16(8443), cfg.TLS.Port)
+
+	cfg, err = config.ProvideFromKey[defaultedCfg]("srv")(providerFromYAML(t, "srv:\n  enabled: false\n  retries: 0\n  timeout: 0s\n"))
+	require.NoError(t, err)
+	require.False(t, cfg.Enabled, "explicit false is kept")
+	require.Zero(t, cfg.Retries, "explicit 0 is kept")
+	require.Zero(t, cfg.Timeout, "explicit 0s is kept")
+	require.Equal(t, uint16(8443), cfg.TLS.Port)
+
+	type badDefault struct {
+		Timeout time.Duration `yaml:"timeout" default:"soon"`
+	}
+	_, err = config.ProvideFromKey[badDefault]("srv")(providerFromYAML(t, "srv: {}\n"))
+	require.ErrorContains(t, err, "invalid default for srv.timeout")
+}
@@ -0,0 +1,63 @@
+package configkit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Deprecation reports a deprecated field that is set in the configuration.
+type Deprecation struct {
+	Path    string `json:"path"`    // YAML dot path relative to the requirement key
+	Message string `json:"message"` // value of the `deprecated:"..."` tag
+}
+
+func (d Deprecation) String() string { return fmt.Sprintf("%s: deprecated: %s", d.Path, d.Message) }
+
+// warnDeprecated logs every deprecated field of t that is present in raw.
+func warnDeprecated(key string, raw any, t reflect.Type) {
+	for _, d := range findDeprecated(raw, t, "") {
+		logger.Load().Warn("config: deprecated field in use",
+			zap.String("key", joinKey(key, d.Path)),
+			zap.String("hint", d.Message),
+		)
+	}
+}
+
+// findDeprecated walks raw alongside struct type t and returns the fields
+// tagged `deprecated:"..."` whose YAML key is present.
+func findDeprecated(raw any, t reflect.Type, prefix string) []Deprecation {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	m, ok := normalize(raw).(map[string]any)
+	if t.Kind() != reflect.Struct || !ok {
+		return nil
+	}
+
+	var out []Deprecation
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, inline := parseYAMLTag(f.Tag.Get("yaml"), f)
+		if inline {
+			out = append(out, findDeprecated(m, f.Type, prefix)...)
+			continue
+		}
+		v, present := m[name]
+		if name == "-" || !present {
+			continue
+		}
+		path := joinKey(prefix, name)
+		if msg, ok := f.Tag.Lookup("deprecated"); ok {
+			out = append(out, Deprecation{Path: path, Message: msg})
+		}
+		out = append(out, findDeprecated(v, f.Type, path)...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
@@ -0,0 +1,84 @@
+package configkit_test
+
+import (
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type deprecatedTLS struct {
+	Cert string `yaml:"cert"`
+	File string `yaml:"file" deprecated:"use cert instead"`
+}
+
+type deprecatedCfg struct {
+	Addr    string        `yaml:"addr"`
+	Listen  string        `yaml:"listen" deprecated:"use addr instead"`
+	TLS     deprecatedTLS `yaml:"tls"`
+	Verbose bool          `yaml:"verbose" deprecated:"use logger.level instead"`
+}
+
+func TestProvideFromKey_WarnsOnDeprecatedFields(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	config.SetLogger(zap.New(core))
+	t.Cleanup(func() { config.SetLogger(nil) })
+
+	p := providerFromYAML(t, "svc:\n  listen: \":8080\"\n  tls:\n    file: a.pem\n")
+	cfg, err := config.ProvideFromKey[deprecatedCfg]("svc")(p)
+	require.NoError(t, err)
+	require.Equal(t, ":8080", cfg.Listen)
+
+	entries := logs.FilterMessage("config: deprecated field in use").All()
+	require.Len(t, entries, 2)
+	require.Equal(t, "svc.listen", entries[0].ContextMap()["key"])
+	require.Equal(t, "use addr instead", entries[0].ContextMap()["hint"])
+	require.Equal(t, "svc.tls.file", entries[1].ContextMap()["key"])
+}
+
+func TestCheckAndSpec_SurfaceDeprecatedFields(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterRequirement("svc", deprecatedCfg{})
+
+	res := config.Check(providerFromYAML(t, "svc:\n  addr: \":1\"\n  verbose: true\n"))
+	require.Len(t, res, 1)
+	require.True(t, res[0].OK, "deprecations must not fail the check")
+	require.Equal(t, []config.Deprecation{{Path: "verbose", Message: "use logger.level instead"}}, res[0].Deprecated)
+
+	specs, err := config.Spec(config.Requirements()[0])
+	require.NoError(t, err)
+	got := map[string]string{}
+	for _, s := range specs {
+		got[s.Path] = s.Deprecated
+	}
+	require.Equal(t, "use addr instead", got["listen"])
+	require.Equal(t, "use cert instead", got["tls.file"])
+	require.Empty(t, got["addr"])
+}
+
+func TestModule_LogsToAppLogger(t *testing.T) {
+	config.SetLogger(nil)
+	t.Cleanup(func() { config.SetLogger(nil) })
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+
+	// Like logkit, the logger is built from config, so loading and the
+	// deprecation warning happen before it exists.
+	core, logs := observer.New(zapcore.InfoLevel)
+	app := fxtest.New(t,
+		config.Module(config.WithEmbeddedBytes([]byte("svc:\n  listen: \":8080\"\n"))),
+		fx.Provide(config.ProvideFromKey[deprecatedCfg]("svc")),
+		fx.Provide(func(*deprecatedCfg) *zap.Logger { return zap.New(core) }),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	require.Equal(t, 1, logs.FilterMessage("config: loaded").Len())
+	require.Equal(t, 1, logs.FilterMessage("config: deprecated field in use").Len())
+}
@@ -0,0 +1,176 @@
+package configkit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	uber "go.uber.org/config"
+	"go.uber.org/zap"
+)
+
+// ChangeKind classifies a Change.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is one leaf key whose value differs between two configs. Lists are
+// compared as a whole. Before and After are redacted like Redact; the
+// comparison itself uses the real values, so a rotated secret still shows up.
+type Change struct {
+	Key    string
+	Kind   ChangeKind
+	Before any // nil when added
+	After  any // nil when removed
+}
+
+// Diff compares the full trees of from and to and returns every leaf that was
+// added, removed, or modified, sorted by key.
+func Diff(from, to Provider) ([]Change, error) {
+	return diff(from, to, true)
+}
+
+// DiffRaw is like Diff but reports secret values unredacted. Use it only
+// where the caller explicitly asked to see secrets.
+func DiffRaw(from, to Provider) ([]Change, error) {
+	return diff(from, to, false)
+}
+
+func diff(from, to Provider, redacted bool) ([]Change, error) {
+	a, err := leaves(from)
+	if err != nil {
+		return nil, fmt.Errorf("config: diff: %w", err)
+	}
+	b, err := leaves(to)
+	if err != nil {
+		return nil, fmt.Errorf("config: diff: %w", err)
+	}
+	show := func(l leaf) any {
+		if redacted {
+			return l.shown()
+		}
+		return l.value
+	}
+	var out []Change
+	for k, av := range a {
+		bv, ok := b[k]
+		switch {
+		case !ok:
+			out = append(out, Change{Key: k, Kind: ChangeRemoved, Before: show(av)})
+		case !reflect.DeepEqual(av.value, bv.value):
+			// A key loaded as a secret on either side is masked on both.
+			av.secret = av.secret || bv.secret
+			bv.secret = av.secret
+			out = append(out, Change{Key: k, Kind: ChangeModified, Before: show(av), After: show(bv)})
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, Change{Key: k, Kind: ChangeAdded, After: show(bv)})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// leaf is a flattened config value and whether it must be redacted.
+type leaf struct {
+	value  any
+	secret bool
+}
+
+func (l leaf) shown() any {
+	if l.secret {
+		return "***"
+	}
+	return l.value
+}
+
+func leaves(p Provider) (map[string]leaf, error) {
+	var raw any
+	if err := p.Get(uber.Root).Populate(&raw); err != nil {
+		return nil, err
+	}
+	out := map[string]leaf{}
+	flattenLeaves(secretsOf(p), "", normalize(raw), false, out)
+	return out, nil
+}
+
+func flattenLeaves(secrets secretSet, prefix string, v any, secret bool, out map[string]leaf) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		if prefix != "" && v != nil {
+			out[prefix] = leaf{value: v, secret: secret}
+		}
+		return
+	}
+	for k, val := range m {
+		path := joinKey(prefix, k)
+		flattenLeaves(secrets, path, val, secret || isSecretKey(k) || secrets.has(path), out)
+	}
+}
+
+// WithOverrideReport logs, after loading, every key set by WithEmbeddedBytes
+// defaults that a higher layer (files, mounted directories, secrets, or
+// environment expansion) changed or cleared. See Overrides.
+func WithOverrideReport() ModuleOption {
+	return func(o *moduleOpts) {
+		o.overrideReport = true
+	}
+}
+
+// Overrides compares the embedded defaults of p (the WithEmbeddedBytes
+// layers, with environment expansion applied) against p itself and returns
+// the defaults that higher layers modified or removed. Keys that only exist
+// in higher layers are not overrides and are left out. p must have been
+// built by configkit.
+func Overrides(p *uber.YAML) ([]Change, error) {
+	chain, ok := chainFor(p)
+	if !ok {
+		return nil, fmt.Errorf("config: provider was not built by configkit; cannot compare overrides")
+	}
+	var base []uber.YAMLOption
+	for _, l := range chain {
+		if l.name == embeddedLayerName {
+			base = append(base, l.src)
+		}
+	}
+	if len(base) == 0 {
+		return nil, nil
+	}
+	defaults, err := uber.NewYAML(append(base, envLayer().src)...)
+	if err != nil {
+		return nil, fmt.Errorf("config: embedded defaults: %w", err)
+	}
+	changes, err := Diff(defaults, p)
+	if err != nil {
+		return nil, err
+	}
+	out := changes[:0]
+	for _, c := range changes {
+		if c.Kind != ChangeAdded {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// logOverrides logs the result of Overrides for p.
+func logOverrides(p *uber.YAML) {
+	changes, err := Overrides(p)
+	if err != nil {
+		logger.Load().Warn("config: override report failed", zap.Error(err))
+		return
+	}
+	for _, c := range changes {
+		logger.Load().Info("config: embedded default overridden",
+			zap.String("key", c.Key),
+			zap.Any("default", c.Before),
+			zap.Any("value", c.After),
+		)
+	}
+}
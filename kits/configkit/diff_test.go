@@ -0,0 +1,60 @@
+package configkit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDiff(t *testing.T) {
+	from := providerFromYAML(t, "http:\n  addr: \":8080\"\n  tags: [a]\ndb:\n  password: old\n  host: a\n")
+	to := providerFromYAML(t, "http:\n  addr: \":9090\"\n  tags: [a]\ndb:\n  password: new\nlog:\n  level: debug\n")
+
+	changes, err := config.Diff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []config.Change{
+		{Key: "db.host", Kind: config.ChangeRemoved, Before: "a"},
+		{Key: "db.password", Kind: config.ChangeModified, Before: "***", After: "***"},
+		{Key: "http.addr", Kind: config.ChangeModified, Before: ":8080", After: ":9090"},
+		{Key: "log.level", Kind: config.ChangeAdded, After: "debug"},
+	}, changes)
+
+	raw, err := config.DiffRaw(from, to)
+	require.NoError(t, err)
+	require.Contains(t, raw, config.Change{Key: "db.password", Kind: config.ChangeModified, Before: "old", After: "new"})
+}
+
+func TestWithOverrideReport(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	config.SetLogger(zap.New(core))
+	t.Cleanup(func() { config.SetLogger(nil) })
+
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("http:\n  addr: \":9090\"\nextra: true\n"))
+
+	defaults := []byte("http:\n  addr: \":8080\"\n  read_timeout_ms: 100\n")
+	p, err := config.Load(
+		config.WithEmbeddedBytes(defaults),
+		config.WithOverrideReport(),
+	)
+	require.NoError(t, err)
+
+	changes, err := config.Overrides(p)
+	require.NoError(t, err)
+	require.Equal(t, []config.Change{
+		{Key: "http.addr", Kind: config.ChangeModified, Before: ":8080", After: ":9090"},
+	}, changes, "keys added by files are not overrides")
+
+	entries := logs.FilterMessage("config: embedded default overridden").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "http.addr", entries[0].ContextMap()["key"])
+}
@@ -1,12 +1,15 @@
 package configkit
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
 
+	"github.com/go-playground/validator/v10"
 	uber "go.uber.org/config"
 )
 
@@ -34,6 +37,7 @@ var (
 	reqMu   sync.Mutex
 	reqSeen = map[string]struct{}{}
 	reqs    []reqEntry
+	specs   = map[string][]FieldSpec{} // explicit, by key; see RegisterSpec
 
 	knownMu    sync.Mutex
 	knownTypes = map[string]reflect.Type{}
@@ -130,15 +134,40 @@ type FieldSpec struct {
 	Path     string // YAML dot path relative to Requirement.Key
 	Type     string // Go kind or type name
 	Required bool   // true if validate tag contains "required"
+	// Rules lists the other field-level validate rules, e.g. "oneof=a b" or
+	// "gte=0". Rules applied to elements after `dive` are not included.
+	Rules      []string
+	Deprecated string // value of the `deprecated` tag, if any
+	// Description is the value of the `doc` tag, if any, e.g.
+	// `doc:"listen address, host:port"`.
+	Description string
+	// Secret is true for fields tagged `secret:"true"`; see RedactStruct.
+	Secret bool
+}
+
+// RegisterSpec declares the fields of the config under key explicitly, for
+// types whose shape reflection cannot see, such as types implementing
+// yaml.Unmarshaler. Spec, and therefore Skeleton and stackctl's discovery
+// output, return these fields for key instead of the reflected ones.
+// Registering again for the same key replaces the earlier spec.
+func RegisterSpec(key string, fields []FieldSpec) {
+	reqMu.Lock()
+	specs[key] = append([]FieldSpec(nil), fields...)
+	reqMu.Unlock()
 }
 
 // Spec returns a best-effort field specification for the given requirement.
-// It infers YAML field names from `yaml` tags when present, falling back to
+// Fields registered with RegisterSpec for req.Key take precedence. Otherwise
+// YAML field names are inferred from `yaml` tags when present, falling back to
 // lowercased field names. Embedded/inline fields are flattened.
 func Spec(req Requirement) ([]FieldSpec, error) {
 	reqMu.Lock()
 	defer reqMu.Unlock()
 
+	if fields, ok := specs[req.Key]; ok {
+		return append([]FieldSpec(nil), fields...), nil
+	}
+
 	// Find the matching entry to get the reflect.Type
 	var match *reqEntry
 	for i := range reqs {
@@ -196,25 +225,70 @@ func walkStruct(t reflect.Type, prefix string, out *[]FieldSpec) {
 		for base.Kind() == reflect.Ptr {
 			base = base.Elem()
 		}
-		switch base.Kind() {
-		case reflect.Struct:
+		spec := FieldSpec{
+			Required:    required,
+			Rules:       validateRules(valTag),
+			Deprecated:  f.Tag.Get("deprecated"),
+			Description: f.Tag.Get("doc"),
+			Secret:      f.Tag.Get("secret") == "true",
+		}
+		switch {
+		case base.Kind() == reflect.Struct:
 			// Recurse into nested structs. If inline, prefix is unchanged.
 			walkStruct(base, path, out)
+		case name == "-":
+			// Not decoded from YAML.
+		case base.Name() == "" && (base.Kind() == reflect.Map || base.Kind() == reflect.Slice || base.Kind() == reflect.Array):
+			walkElem(base, path, spec, out)
 		default:
 			// Record leaf field
-			if name == "-" {
-				continue
-			}
 			kind := base.Kind().String()
 			if base.Name() != "" {
 				// Prefer concrete name if present
 				kind = base.Name()
 			}
-			*out = append(*out, FieldSpec{Path: path, Type: kind, Required: required})
+			spec.Path, spec.Type = path, kind
+			*out = append(*out, spec)
 		}
 	}
 }
 
+// walkElem describes an unnamed map, slice, or array field at path. Struct
+// elements are walked under "path.<key>" (maps) or "path[]" (lists); scalar
+// elements yield a single leaf, typed "string" for a map of strings or
+// "[]string" for a list of them.
+func walkElem(t reflect.Type, path string, spec FieldSpec, out *[]FieldSpec) {
+	elem := t.Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if t.Kind() == reflect.Map {
+		path += "." + mapKeyPlaceholder
+	} else {
+		path += listSuffix
+	}
+	if elem.Kind() == reflect.Struct {
+		walkStruct(elem, path, out)
+		return
+	}
+	typ := elem.Kind().String()
+	if elem.Name() != "" {
+		typ = elem.Name()
+	}
+	if t.Kind() != reflect.Map {
+		path = strings.TrimSuffix(path, listSuffix)
+		typ = "[]" + typ
+	}
+	spec.Path, spec.Type = path, typ
+	*out = append(*out, spec)
+}
+
+// Path segments used by walkElem for map keys and list elements.
+const (
+	mapKeyPlaceholder = "<key>"
+	listSuffix        = "[]"
+)
+
 func parseYAMLTag(tag string, f reflect.StructField) (name string, inline bool) {
 	// Prefer YAML tag. Fallback to JSON tag if YAML absent.
 	tag = strings.TrimSpace(tag)
@@ -253,13 +327,33 @@ func trimPkg(s string) string {
 	return s
 }
 
+// validateRules returns the field-level rules of a validate tag other than
+// required, omitempty, and the ci option.
+func validateRules(tag string) []string {
+	var out []string
+	for _, tok := range strings.Split(tag, ",") {
+		switch tok = strings.TrimSpace(tok); tok {
+		case "", "required", "omitempty", "ci":
+		case "dive":
+			return out
+		default:
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
 func hasRequired(tag string) bool {
 	if tag == "" {
 		return false
 	}
 	for _, tok := range strings.Split(tag, ",") {
-		if strings.TrimSpace(tok) == "required" {
+		switch strings.TrimSpace(tok) {
+		case "required":
 			return true
+		case "dive":
+			// Rules after dive apply to elements, not the field itself.
+			return false
 		}
 	}
 	return false
@@ -276,7 +370,13 @@ func RegisterKnown(key string, sample any) {
 	if sample == nil {
 		return
 	}
-	t := reflect.TypeOf(sample)
+	RegisterKnownType(key, reflect.TypeOf(sample))
+}
+
+// RegisterKnownType registers a known module key using a reflect.Type, for
+// dynamically loaded modules that have no typed sample value. Pointer types
+// are unwrapped.
+func RegisterKnownType(key string, t reflect.Type) {
 	if t == nil {
 		return
 	}
@@ -325,12 +425,14 @@ type CheckResult struct {
 	Err     error
 	Issues  []string // formatted validator issues: yaml.path: rule
 	Unknown []string // unknown keys detected in YAML subtree
+	// Deprecated lists deprecated fields that are set. They do not affect OK.
+	Deprecated []Deprecation
 }
 
 // Check validates all discovered requirements against the provided YAML
 // provider. It attempts to populate and validate each config subtree using the
 // same rules as ProvideFromKey (including `validate` struct tags).
-func Check(p *uber.YAML) []CheckResult {
+func Check(p Provider) []CheckResult {
 	reqMu.Lock()
 	snapshot := make([]reqEntry, len(reqs))
 	copy(snapshot, reqs)
@@ -338,34 +440,7 @@ func Check(p *uber.YAML) []CheckResult {
 
 	out := make([]CheckResult, 0, len(snapshot))
 	for _, r := range snapshot {
-		// Build a pointer to base struct to populate into.
-		v := reflect.New(r.base)
-		// Populate from YAML subtree
-		err := p.Get(r.key).Populate(v.Interface())
-		var issues []string
-		if err == nil {
-			// Validate using the shared validator instance.
-			if verr := validate.Struct(v.Interface()); verr != nil {
-				issues = append(issues, formatValidationIssues(verr, r.base)...)
-				err = verr
-			}
-		}
-		// Unknown keys detection: compare YAML subtree to struct fields.
-		var raw any
-		if err := p.Get(r.key).Populate(&raw); err != nil {
-			raw = nil
-		}
-		unknown := findUnknownKeys(raw, r.base, "")
-		ok := err == nil && len(unknown) == 0
-		tname := r.base.Name()
-		if pkg := r.base.PkgPath(); pkg != "" {
-			parts := strings.Split(pkg, "/")
-			short := parts[len(parts)-1]
-			if short != "" {
-				tname = short + "." + tname
-			}
-		}
-		out = append(out, CheckResult{Key: r.key, Type: tname, OK: ok, Err: err, Issues: issues, Unknown: unknown})
+		out = append(out, CheckType(p, r.key, r.base))
 	}
 	sort.SliceStable(out, func(i, j int) bool {
 		if out[i].Key == out[j].Key {
@@ -376,6 +451,84 @@ func Check(p *uber.YAML) []CheckResult {
 	return out
 }
 
+// CheckType populates and validates the subtree at key against t, exactly as
+// Check does for each requirement, without registering t as one. It lets tools
+// validate types resolved at runtime, e.g. via KnownType. Pointer types are
+// unwrapped.
+func CheckType(p Provider, key string, t reflect.Type) CheckResult {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Build a pointer to base struct to populate into.
+	v := reflect.New(t)
+	// Populate from YAML subtree
+	var (
+		issues []string
+		err    error
+	)
+	if strict := strictTypeIssues(p, key, t); len(strict) > 0 {
+		issues = append(issues, strict...)
+		err = strictTypesError(key, strict)
+	} else {
+		err = listValue(p, key, t).Populate(v.Interface())
+	}
+	if err == nil {
+		err = applyDefaults(v, key, rawSubtree(p, key))
+	}
+	if err == nil {
+		foldCase(v)
+		// Validate using the shared validator instance.
+		if verr := validate.Struct(v.Interface()); verr != nil {
+			issues = append(issues, formatValidationIssues(verr, t)...)
+			err = verr
+		}
+	}
+	// Unknown keys detection: compare YAML subtree to struct fields.
+	var raw any
+	if err := p.Get(key).Populate(&raw); err != nil {
+		raw = nil
+	}
+	unknown := findUnknownKeys(raw, t, "")
+	deprecated := findDeprecated(raw, t, "")
+	ok := err == nil && len(unknown) == 0
+	tname := t.Name()
+	if pkg := t.PkgPath(); pkg != "" {
+		parts := strings.Split(pkg, "/")
+		short := parts[len(parts)-1]
+		if short != "" {
+			tname = short + "." + tname
+		}
+	}
+	return CheckResult{Key: key, Type: tname, OK: ok, Err: err, Issues: issues, Unknown: unknown, Deprecated: deprecated}
+}
+
+// ValidateAll registers every known module (see RegisterKnown) as a
+// requirement and checks the full set against p, so modules are validated even
+// if nothing has called ProvideFromKey for them yet. It returns all results
+// and a joined error describing each failing requirement.
+func ValidateAll(p Provider) ([]CheckResult, error) {
+	knownMu.Lock()
+	for key, t := range knownTypes {
+		registerRequirementType(key, t)
+	}
+	knownMu.Unlock()
+
+	results := Check(p)
+	var errs []error
+	for _, r := range results {
+		if r.OK {
+			continue
+		}
+		switch {
+		case r.Err != nil:
+			errs = append(errs, fmt.Errorf("config: %s (%s): %w", r.Key, r.Type, r.Err))
+		case len(r.Unknown) > 0:
+			errs = append(errs, fmt.Errorf("config: %s (%s): unknown keys %s", r.Key, r.Type, strings.Join(r.Unknown, ", ")))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
 // ResetDiscoveryForTests clears the internal registry. Exported for tests; do not
 // use in application code.
 func ResetDiscoveryForTests() {
@@ -383,17 +536,29 @@ func ResetDiscoveryForTests() {
 	defer reqMu.Unlock()
 	reqSeen = map[string]struct{}{}
 	reqs = nil
+	specs = map[string][]FieldSpec{}
 }
 
 // --- Validation issue formatting ---
 
-// formatValidationIssues converts validator.ValidationErrors into YAML-like paths.
+// formatValidationIssues converts validator.ValidationErrors into YAML-like
+// paths, e.g. `trace_sampler: "alway_on" not in [parent_ratio always_on]`.
+// Other errors are split into lines, with a best-effort parse of validator's
+// message format.
 func formatValidationIssues(err error, root reflect.Type) []string {
-	// Use reflection of struct namespace to yaml path mapping.
-	// If it's not a validator error, return a single generic message.
-	// We avoid importing validator types here to keep this helper decoupled.
-	// When it's not a known type, we fallback to err.Error().
-	// Best-effort only.
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		out := make([]string, 0, len(verrs))
+		for _, fe := range verrs {
+			path := yamlPathFromStructNS(fe.StructNamespace(), root)
+			if path == "" {
+				path = fe.Field()
+			}
+			out = append(out, fmt.Sprintf("%s: %s", path, issueMessage(fe)))
+		}
+		return out
+	}
+
 	// We detect common format substrings "Field validation for 'X' failed on the 'rule' tag".
 	msg := err.Error()
 	// Quick path: split by newline for multiple field errors.
@@ -419,6 +584,15 @@ func formatValidationIssues(err error, root reflect.Type) []string {
 	return out
 }
 
+// issueMessage describes a failed rule. Enum-style rules spell out the
+// allowed values; others are reported by tag name.
+func issueMessage(fe validator.FieldError) string {
+	if fe.Tag() == "oneof" {
+		return fmt.Sprintf("%q not in [%s]", fmt.Sprint(fe.Value()), fe.Param())
+	}
+	return fe.Tag()
+}
+
 func extractFieldAndRule(s string) (field, rule string) {
 	// Looks for patterns:
 	// "Field validation for 'Nested.Value' failed on the 'min' tag"
@@ -578,15 +752,80 @@ func findUnknownKeys(y interface{}, t reflect.Type, prefix string) []string {
 
 // --- YAML skeleton generation ---
 
+// BootstrapYAML renders one example document covering every known module
+// (see RegisterKnown), each section headed by a comment naming its Go type.
+// Placeholders are zero values, so the result parses but required fields
+// still need filling in.
+func BootstrapYAML() (string, error) {
+	var b strings.Builder
+	b.WriteString("# Generated from the modules linked into this binary.\n")
+	b.WriteString("# Fields marked \"# required\" must be set before the service starts.\n")
+	for _, req := range Known() {
+		if t, ok := KnownType(req.Key); ok {
+			registerRequirementType(req.Key, t)
+		}
+		sk, err := Skeleton(req)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n# %s (%s)\n", req.Key, req.Type)
+		b.WriteString(sk)
+	}
+	out := b.String()
+	if _, err := uber.NewYAML(uber.Source(strings.NewReader(out))); err != nil {
+		return "", fmt.Errorf("config: bootstrap document is not valid YAML: %w", err)
+	}
+	return out, nil
+}
+
 // Skeleton renders an example YAML snippet for the requirement key.
 func Skeleton(req Requirement) (string, error) {
-	specs, err := Spec(req)
+	root, err := skeletonTree(req)
+	if err != nil {
+		return "", err
+	}
+	// Render YAML
+	var b strings.Builder
+	if req.Key != "" {
+		b.WriteString(req.Key)
+		b.WriteString(":\n")
+		renderNode(&b, root, 2)
+	} else {
+		renderNode(&b, root, 0)
+	}
+	return b.String(), nil
+}
+
+// SkeletonJSON renders the example of Skeleton as indented JSON. JSON has no
+// comments, so each object lists its required fields in a sibling
+// "_required" array instead of marking them "# required".
+func SkeletonJSON(req Requirement) (string, error) {
+	root, err := skeletonTree(req)
+	if err != nil {
+		return "", err
+	}
+	var doc any = jsonNode(root)
+	if req.Key != "" {
+		doc = map[string]any{req.Key: doc}
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
 		return "", err
 	}
-	// Build nested map structure from paths
-	type node map[string]interface{}
-	root := node{}
+	return string(b) + "\n", nil
+}
+
+// skeletonNode is a section of a skeleton: nested sections, or FieldSpec
+// leaves. Keys ending in listSuffix hold the example entry of a list.
+type skeletonNode = map[string]any
+
+// skeletonTree nests the fields of req by path, naming map keys "example".
+func skeletonTree(req Requirement) (skeletonNode, error) {
+	specs, err := Spec(req)
+	if err != nil {
+		return nil, err
+	}
+	root := skeletonNode{}
 	for _, s := range specs {
 		if s.Path == "" {
 			continue
@@ -594,28 +833,54 @@ func Skeleton(req Requirement) (string, error) {
 		parts := strings.Split(s.Path, ".")
 		cur := root
 		for i, seg := range parts {
+			if seg == mapKeyPlaceholder {
+				seg = "example"
+			}
 			if i == len(parts)-1 {
 				// leaf
-				cur[seg] = placeholderFor(s)
+				cur[seg] = s
 			} else {
 				if _, ok := cur[seg]; !ok {
-					cur[seg] = node{}
+					cur[seg] = skeletonNode{}
 				}
-				nxt, _ := cur[seg].(node)
+				nxt, _ := cur[seg].(skeletonNode)
 				cur = nxt
 			}
 		}
 	}
-	// Render YAML
-	var b strings.Builder
-	if req.Key != "" {
-		b.WriteString(req.Key)
-		b.WriteString(":\n")
-		renderNode(&b, root, 2)
-	} else {
-		renderNode(&b, root, 0)
+	return root, nil
+}
+
+// jsonNode converts a skeleton section to JSON values, listing required
+// leaves under "_required".
+func jsonNode(n skeletonNode) map[string]any {
+	out := make(map[string]any, len(n))
+	var required []string
+	for k, v := range n {
+		switch vv := v.(type) {
+		case skeletonNode:
+			if name, ok := strings.CutSuffix(k, listSuffix); ok {
+				out[name] = []any{jsonNode(vv)}
+				continue
+			}
+			out[k] = jsonNode(vv)
+		case FieldSpec:
+			if vv.Required {
+				required = append(required, k)
+				vv.Required = false
+			}
+			var ph any
+			if err := json.Unmarshal([]byte(placeholderFor(vv)), &ph); err != nil {
+				ph = nil // no typed placeholder, e.g. "<value>"
+			}
+			out[k] = ph
+		}
 	}
-	return b.String(), nil
+	if len(required) > 0 {
+		sort.Strings(required)
+		out["_required"] = required
+	}
+	return out
 }
 
 func renderNode(b *strings.Builder, n map[string]interface{}, indent int) {
@@ -630,15 +895,27 @@ func renderNode(b *strings.Builder, n map[string]interface{}, indent int) {
 		v := n[k]
 		switch vv := v.(type) {
 		case map[string]interface{}:
+			if name, ok := strings.CutSuffix(k, listSuffix); ok {
+				// One example list entry: "- " takes the place of the
+				// indentation of its first line.
+				var item strings.Builder
+				renderNode(&item, vv, indent+4)
+				b.WriteString(pad)
+				b.WriteString(name)
+				b.WriteString(":\n")
+				b.WriteString(pad + "  - ")
+				b.WriteString(strings.TrimPrefix(item.String(), pad+"    "))
+				continue
+			}
 			b.WriteString(pad)
 			b.WriteString(k)
 			b.WriteString(":\n")
 			renderNode(b, vv, indent+2)
-		default:
+		case FieldSpec:
 			b.WriteString(pad)
 			b.WriteString(k)
 			b.WriteString(": ")
-			fmt.Fprint(b, vv)
+			b.WriteString(placeholderFor(vv))
 			b.WriteString("\n")
 		}
 	}
@@ -656,7 +933,15 @@ func placeholderFor(s FieldSpec) string {
 		ph = "0.0"
 	case "bool":
 		ph = "false"
+	case "map":
+		ph = "{}"
+	case "slice", "array":
+		ph = "[]"
 	default:
+		if strings.HasPrefix(t, listSuffix) {
+			ph = "[]"
+			break
+		}
 		if strings.Contains(t, "duration") {
 			ph = "\"1s\""
 		}
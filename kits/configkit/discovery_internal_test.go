@@ -2,10 +2,12 @@ package configkit_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	config "github.com/froppa/stackkit/kits/configkit"
 	pkghttp "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/require"
 	uber "go.uber.org/config"
 )
@@ -53,3 +55,196 @@ func TestDiscovery_ListAndCheck(t *testing.T) {
 	}
 	require.True(t, hasAddr, "expected addr to be marked required in spec")
 }
+
+func TestSpec_IncludesValidateRules(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	type sampled struct {
+		Sampler string   `yaml:"sampler" validate:"required,ci,oneof=a b"`
+		Rate    float64  `yaml:"rate" validate:"omitempty,gte=0,lte=1"`
+		Hosts   []string `yaml:"hosts" validate:"min=1,dive,hostname"`
+	}
+	config.RegisterRequirement("sampled", sampled{})
+
+	fields, err := config.Spec(config.Requirements()[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"oneof=a b"}, fields[0].Rules)
+	require.True(t, fields[0].Required)
+	require.Equal(t, []string{"gte=0", "lte=1"}, fields[1].Rules)
+	require.Equal(t, []string{"min=1"}, fields[2].Rules)
+}
+
+func TestValidateAll_RegistersKnownModules(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	require.Empty(t, config.Requirements())
+
+	// httpkit registers "http" as known via init; nothing called ProvideFromKey.
+	res, err := config.ValidateAll(providerFromYAML(t, "http:\n  enable_pprof: true\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config: http (httpkit.Config)")
+
+	var httpRes *config.CheckResult
+	for i := range res {
+		if res[i].Key == "http" {
+			httpRes = &res[i]
+		}
+	}
+	require.NotNil(t, httpRes, "known http module should be validated")
+	require.False(t, httpRes.OK)
+
+	res, err = config.ValidateAll(providerFromYAML(t, "http:\n  addr: \":8080\"\n  bogus: 1\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bogus")
+	require.NotEmpty(t, res)
+}
+
+func TestRegisterKnownType(t *testing.T) {
+	type pluginConfig struct {
+		Enabled bool `yaml:"enabled"`
+	}
+	config.RegisterKnownType("plugin.example", reflect.TypeOf((**pluginConfig)(nil)).Elem())
+	config.RegisterKnownType("plugin.nil", nil)
+
+	got, ok := config.KnownType("plugin.example")
+	require.True(t, ok)
+	require.Equal(t, reflect.TypeOf(pluginConfig{}), got, "pointer types should be unwrapped")
+
+	_, ok = config.KnownType("plugin.nil")
+	require.False(t, ok)
+
+	var found bool
+	for _, r := range config.Known() {
+		if r.Key == "plugin.example" {
+			found = true
+			require.Equal(t, "configkit_test.pluginConfig", r.Type)
+		}
+	}
+	require.True(t, found, "Known should list types registered by reflect.Type")
+}
+
+type enumCfg struct {
+	Sampler string `yaml:"trace_sampler" validate:"omitempty,oneof=parent_ratio always_on always_off"`
+	Nested  struct {
+		Level string `yaml:"level" validate:"oneof=debug info"`
+	} `yaml:"nested"`
+}
+
+func TestOneofIssuesListAllowedValues(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterRequirement("telemetry", enumCfg{})
+
+	p := providerFromYAML(t, "telemetry:\n  trace_sampler: alway_on\n  nested:\n    level: trace\n")
+	res := config.Check(p)
+	require.Len(t, res, 1)
+	require.Equal(t, []string{
+		`trace_sampler: "alway_on" not in [parent_ratio always_on always_off]`,
+		`nested.level: "trace" not in [debug info]`,
+	}, res[0].Issues)
+
+	_, err := config.ProvideFromKey[enumCfg]("telemetry")(p)
+	require.ErrorContains(t, err, `telemetry.trace_sampler: "alway_on" not in [parent_ratio always_on always_off]; telemetry.nested.level: "trace" not in [debug info]`)
+	var verrs validator.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+}
+
+type bootstrapCfg struct {
+	Addr   string            `yaml:"addr" validate:"required"`
+	Labels map[string]string `yaml:"labels"`
+	Peers  []string          `yaml:"peers"`
+	Routes []struct {
+		Path   string `yaml:"path"`
+		Weight int    `yaml:"weight"`
+	} `yaml:"routes"`
+	TLS struct {
+		Cert string `yaml:"cert"`
+	} `yaml:"tls"`
+}
+
+func TestBootstrapYAML(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterKnown("bootstrap", (*bootstrapCfg)(nil))
+
+	doc, err := config.BootstrapYAML()
+	require.NoError(t, err)
+	require.Contains(t, doc, "# bootstrap (configkit_test.bootstrapCfg)\nbootstrap:\n"+
+		"  addr: \"\"  # required\n"+
+		"  labels:\n"+
+		"    example: \"\"\n"+
+		"  peers: []\n"+
+		"  routes:\n"+
+		"    - path: \"\"\n"+
+		"      weight: 0\n"+
+		"  tls:\n"+
+		"    cert: \"\"\n")
+
+	// The document loads, and each section fits its module's type.
+	p := providerFromYAML(t, doc)
+	var got bootstrapCfg
+	require.NoError(t, p.Get("bootstrap").Populate(&got))
+	for _, r := range config.Check(p) {
+		require.Empty(t, r.Unknown, r.Key)
+	}
+}
+
+func TestSkeletonJSON_ListsRequiredFields(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterRequirement("bootstrap", bootstrapCfg{})
+
+	doc, err := config.SkeletonJSON(config.Requirements()[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"bootstrap": {
+		"_required": ["addr"],
+		"addr": "",
+		"labels": {"example": ""},
+		"peers": [],
+		"routes": [{"path": "", "weight": 0}],
+		"tls": {"cert": ""}
+	}}`, doc)
+}
+
+// cidrList decodes from a single comma-separated string, which reflection
+// on its named type cannot reveal.
+type cidrList []string
+
+func (c *cidrList) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	*c = cidrList{s}
+	return nil
+}
+
+func TestRegisterSpec_OverridesReflection(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+
+	type firewall struct {
+		Allow cidrList `yaml:"allow"`
+	}
+	_ = config.ProvideFromKey[firewall]("firewall")
+	req := config.Requirements()[0]
+
+	fields, err := config.Spec(req)
+	require.NoError(t, err)
+	require.Equal(t, "cidrList", fields[0].Type)
+
+	config.RegisterSpec("firewall", []config.FieldSpec{
+		{Path: "allow", Type: "string", Required: true},
+		{Path: "log.level", Type: "string"},
+	})
+	fields, err = config.Spec(req)
+	require.NoError(t, err)
+	require.Equal(t, []config.FieldSpec{
+		{Path: "allow", Type: "string", Required: true},
+		{Path: "log.level", Type: "string"},
+	}, fields)
+
+	sk, err := config.Skeleton(req)
+	require.NoError(t, err)
+	require.Equal(t, "firewall:\n  allow: \"\"  # required\n  log:\n    level: \"\"\n", sk)
+}
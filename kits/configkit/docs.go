@@ -0,0 +1,99 @@
+package configkit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ModuleDoc documents the config contract of one key: the Go type it is
+// populated into and its fields.
+type ModuleDoc struct {
+	Key     string     `json:"key"`
+	Type    string     `json:"type"`
+	PkgPath string     `json:"pkg_path,omitempty"`
+	Fields  []FieldDoc `json:"fields"`
+}
+
+// FieldDoc is the JSON form of a FieldSpec.
+type FieldDoc struct {
+	Path        string   `json:"path"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Rules       []string `json:"rules,omitempty"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+}
+
+// Docs returns the config contract of every known module (RegisterKnown) and
+// every requirement registered in this process (ProvideFromKey, ...), sorted
+// by key and type. Fields come from RegisterSpec when declared and from
+// reflection otherwise; descriptions are read from `doc` struct tags.
+func Docs() []ModuleDoc {
+	type entry struct {
+		key string
+		t   reflect.Type
+	}
+	seen := map[string]struct{}{}
+	var entries []entry
+	add := func(key string, t reflect.Type) {
+		if _, ok := seen[typeKey(key, t)]; ok {
+			return
+		}
+		seen[typeKey(key, t)] = struct{}{}
+		entries = append(entries, entry{key, t})
+	}
+
+	knownMu.Lock()
+	for k, t := range knownTypes {
+		add(k, t)
+	}
+	knownMu.Unlock()
+
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	for _, r := range reqs {
+		add(r.key, r.base)
+	}
+
+	out := make([]ModuleDoc, 0, len(entries))
+	for _, e := range entries {
+		fields, ok := specs[e.key]
+		if !ok {
+			walkStruct(e.t, "", &fields)
+		}
+		doc := ModuleDoc{Key: e.key, Type: shortTypeName(e.t), PkgPath: e.t.PkgPath(), Fields: []FieldDoc{}}
+		for _, f := range fields {
+			doc.Fields = append(doc.Fields, FieldDoc(f))
+		}
+		out = append(out, doc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Key == out[j].Key {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// ExportDocs returns Docs as indented JSON, for rendering documentation or
+// checking config changes in CI.
+func ExportDocs() ([]byte, error) {
+	return json.MarshalIndent(Docs(), "", "  ")
+}
+
+// shortTypeName renders t as "pkg.Type", using the last element of its
+// package path.
+func shortTypeName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		name = t.String()
+	}
+	if pkg := t.PkgPath(); pkg != "" {
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	return name
+}
@@ -0,0 +1,47 @@
+package configkit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+type docsHTTP struct {
+	Addr   string `yaml:"addr" validate:"required" doc:"listen address, host:port"`
+	Listen string `yaml:"listen" deprecated:"use addr"`
+	TLS    struct {
+		Cert string `yaml:"cert"`
+	} `yaml:"tls"`
+}
+
+func TestExportDocs(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+
+	config.RegisterRequirement("docs_http", docsHTTP{})
+	config.RegisterRequirement("docs_cidrs", []string{})
+	config.RegisterSpec("docs_cidrs", []config.FieldSpec{{Path: "", Type: "[]string", Description: "allowed networks"}})
+
+	b, err := config.ExportDocs()
+	require.NoError(t, err)
+	var docs []config.ModuleDoc
+	require.NoError(t, json.Unmarshal(b, &docs))
+
+	byKey := map[string]config.ModuleDoc{}
+	for _, d := range docs {
+		byKey[d.Key] = d
+	}
+	require.Equal(t, config.ModuleDoc{
+		Key:     "docs_http",
+		Type:    "configkit_test.docsHTTP",
+		PkgPath: "github.com/froppa/stackkit/kits/configkit_test",
+		Fields: []config.FieldDoc{
+			{Path: "addr", Type: "string", Required: true, Description: "listen address, host:port"},
+			{Path: "listen", Type: "string", Deprecated: "use addr"},
+			{Path: "tls.cert", Type: "string"},
+		},
+	}, byKey["docs_http"])
+	require.Equal(t, []config.FieldDoc{{Type: "[]string", Description: "allowed networks"}}, byKey["docs_cidrs"].Fields)
+}
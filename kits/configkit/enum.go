@@ -0,0 +1,63 @@
+package configkit
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// The `ci` validate option marks a string field as case-insensitive: config
+// values are lowercased before validation, so `trace_sampler: Always_On`
+// passes `oneof=always_on ...`. Allowed values must be written in lowercase.
+// As a validation rule it accepts everything.
+//
+//	Sampler string `yaml:"sampler" validate:"omitempty,ci,oneof=always_on always_off"`
+func init() {
+	_ = validate.RegisterValidation("ci", func(validator.FieldLevel) bool { return true })
+}
+
+// foldCase lowercases every settable string field of v, at any depth, whose
+// validate tag carries the `ci` option.
+func foldCase(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			foldCase(v.Elem())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			if fv.Kind() == reflect.String && hasCI(f.Tag.Get("validate")) {
+				fv.SetString(strings.ToLower(fv.String()))
+				continue
+			}
+			foldCase(fv)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			foldCase(v.Index(i))
+		}
+	case reflect.Map:
+		// Map values are not addressable; structs inside maps are left as is.
+	}
+}
+
+// hasCI reports whether the field-level part of a validate tag (before any
+// `dive`) contains the `ci` option.
+func hasCI(tag string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "ci":
+			return true
+		case "dive":
+			return false
+		}
+	}
+	return false
+}
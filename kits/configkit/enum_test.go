@@ -0,0 +1,31 @@
+package configkit_test
+
+import (
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideFromKey_CaseInsensitiveEnums(t *testing.T) {
+	type rule struct {
+		Mode string `yaml:"mode" validate:"ci,oneof=allow deny"`
+	}
+	type cfg struct {
+		Sampler string `yaml:"sampler" validate:"omitempty,ci,oneof=always_on always_off"`
+		Strict  string `yaml:"strict" validate:"omitempty,oneof=a b"`
+		Name    string `yaml:"name"`
+		Rules   []rule `yaml:"rules" validate:"dive"`
+	}
+
+	p := providerFromYAML(t, "app:\n  sampler: Always_On\n  name: MixedCase\n  rules:\n    - mode: DENY\n")
+	got, err := config.ProvideFromKey[cfg]("app")(p)
+	require.NoError(t, err)
+	require.Equal(t, "always_on", got.Sampler)
+	require.Equal(t, "MixedCase", got.Name, "fields without ci keep their case")
+	require.Equal(t, "deny", got.Rules[0].Mode)
+
+	// Without ci, matching stays strict.
+	_, err = config.ProvideFromKey[cfg]("app")(providerFromYAML(t, "app:\n  strict: A\n"))
+	require.ErrorContains(t, err, `app.strict: "A" not in [a b]`)
+}
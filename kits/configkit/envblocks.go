@@ -0,0 +1,74 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	uber "go.uber.org/config"
+)
+
+// Environment returns the deployment environment: the first non-empty of
+// $ENV, $APP_ENV, and $GO_ENV, or "dev". Telemetry uses it as its default
+// environment.
+func Environment() string {
+	for _, k := range []string{"ENV", "APP_ENV", "GO_ENV"} {
+		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
+			return v
+		}
+	}
+	return "dev"
+}
+
+// ProvideFromKeyEnv is like ProvideFromKey for subtrees that keep every
+// environment in one file:
+//
+//	db:
+//	  defaults:
+//	    pool: 10
+//	    host: localhost
+//	  environments:
+//	    prod:
+//	      host: db.internal
+//
+// It populates T from `<key>.defaults` with `<key>.environments.<env>` merged
+// on top, env being Environment(). Maps merge key by key and lists are
+// replaced, as when layering files. File-level overrides apply first, so an
+// environment block beats a default even if that default comes from a
+// higher-precedence file. The result is validated like ProvideFromKey.
+//
+// Unlike ProvideFromKey it does not register the key for Check and Known.
+func ProvideFromKeyEnv[T any](key string) func(provider Provider) (*T, error) {
+	return func(provider Provider) (*T, error) {
+		return populateEnv[T](provider, key, Environment())
+	}
+}
+
+func populateEnv[T any](p Provider, key, env string) (*T, error) {
+	defaults, err := GetMap(p, joinKey(key, "defaults"))
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := GetMap(p, joinKey(key, "environments."+env))
+	if err != nil {
+		return nil, err
+	}
+	merged, err := uber.NewYAML(uber.Static(nestUnder(key, defaults)), uber.Static(nestUnder(key, overrides)))
+	if err != nil {
+		return nil, fmt.Errorf("config: merge %q for environment %q: %w", key, env, err)
+	}
+	rememberStrict(merged, strictnessOf(p))
+	return populate[T](merged, key)
+}
+
+// nestUnder wraps v in one map level per segment of the dotted key.
+func nestUnder(key string, v map[string]any) map[string]any {
+	if key == "" {
+		return v
+	}
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		v = map[string]any{parts[i]: v}
+	}
+	return v
+}
@@ -0,0 +1,63 @@
+package configkit_test
+
+import (
+	"context"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+type envDBConfig struct {
+	Host  string   `yaml:"host" validate:"required"`
+	Pool  int      `yaml:"pool"`
+	Hosts []string `yaml:"hosts"`
+	TLS   struct {
+		Enabled bool   `yaml:"enabled"`
+		CA      string `yaml:"ca"`
+	} `yaml:"tls"`
+}
+
+func TestProvideFromKeyEnv(t *testing.T) {
+	src := `
+svc:
+  db:
+    defaults:
+      host: localhost
+      pool: 10
+      hosts: [a, b]
+      tls: {enabled: false, ca: /etc/ca.pem}
+    environments:
+      prod:
+        host: db.internal
+        hosts: [c]
+        tls: {enabled: true}
+      broken:
+        host: ""
+`
+	p, err := config.NewYAML(context.Background(), config.WithEmbeddedBytes([]byte(src)))
+	require.NoError(t, err)
+	provide := config.ProvideFromKeyEnv[envDBConfig]("svc.db")
+
+	t.Setenv("ENV", "")
+	t.Setenv("APP_ENV", "")
+	t.Setenv("GO_ENV", "")
+	require.Equal(t, "dev", config.Environment())
+	cfg, err := provide(p)
+	require.NoError(t, err)
+	require.Equal(t, "localhost", cfg.Host)
+	require.Equal(t, []string{"a", "b"}, cfg.Hosts)
+
+	t.Setenv("APP_ENV", "prod")
+	cfg, err = provide(p)
+	require.NoError(t, err)
+	require.Equal(t, "db.internal", cfg.Host)
+	require.Equal(t, 10, cfg.Pool)
+	require.Equal(t, []string{"c"}, cfg.Hosts)
+	require.True(t, cfg.TLS.Enabled)
+	require.Equal(t, "/etc/ca.pem", cfg.TLS.CA)
+
+	t.Setenv("ENV", "broken")
+	_, err = provide(p)
+	require.ErrorContains(t, err, "svc.db.host")
+}
@@ -0,0 +1,140 @@
+package configkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	uber "go.uber.org/config"
+	"gopkg.in/yaml.v3"
+)
+
+const fileRefPrefix = "${file:"
+
+// yamlFileSource reads the YAML file at path and resolves its `${file:...}`
+// references. A missing config file is left for uber/config to report, as
+// uber.File would.
+func yamlFileSource(path string) (uber.YAMLOption, []string) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return uber.File(path), nil
+	}
+	return yamlSource(b)
+}
+
+// yamlSource returns b, with `${file:...}` references resolved, as a source,
+// along with the paths of the resolved values. A reference to an unreadable
+// file fails the build of the provider.
+func yamlSource(b []byte) (uber.YAMLOption, []string) {
+	b, secrets, err := expandFileRefs(b)
+	if err != nil {
+		return uber.Source(errReader{err}), nil
+	}
+	return uber.Source(bytes.NewReader(b)), secrets
+}
+
+// expandFileRefs replaces every `${file:/path}` in the scalar values of the
+// YAML document b with the contents of the file, trimmed of surrounding
+// whitespace, and returns the paths of those values as secret. Keys and comments
+// are left alone, and the contents always stay a single string value. It runs
+// before `${VAR}` expansion, so `$` in the contents is escaped as `$$`, and
+// `$${file:...}` stays a literal. A document that does not parse is returned
+// as is, for uber/config to report.
+func expandFileRefs(b []byte) ([]byte, []string, error) {
+	if !bytes.Contains(b, []byte(fileRefPrefix)) {
+		return b, nil, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return b, nil, nil
+	}
+	var secrets []string
+	changed, err := expandNodeRefs(&doc, "", &secrets)
+	if err != nil || !changed {
+		return b, nil, err
+	}
+	out, err := yaml.Marshal(&doc)
+	return out, secrets, err
+}
+
+// expandNodeRefs resolves the references in the scalars below n, found at
+// the dotted path, appending the paths of resolved values to secrets.
+// Elements of lists share the path of the list.
+func expandNodeRefs(n *yaml.Node, path string, secrets *[]string) (bool, error) {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		changed := false
+		for _, c := range n.Content {
+			ok, err := expandNodeRefs(c, path, secrets)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || ok
+		}
+		return changed, nil
+	case yaml.MappingNode:
+		changed := false
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			ok, err := expandNodeRefs(n.Content[i+1], joinKey(path, n.Content[i].Value), secrets)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || ok
+		}
+		return changed, nil
+	case yaml.ScalarNode:
+		v, found, err := expandScalarRefs(n.Value)
+		if err != nil || !found {
+			return false, err
+		}
+		n.Value, n.Tag, n.Style = v, "!!str", yaml.DoubleQuotedStyle
+		markSecret(path, v, secrets)
+		return true, nil
+	}
+	return false, nil
+}
+
+// expandScalarRefs resolves the references in s, reporting whether it had
+// any.
+func expandScalarRefs(s string) (string, bool, error) {
+	if !strings.Contains(s, fileRefPrefix) {
+		return s, false, nil
+	}
+	var (
+		out   strings.Builder
+		found bool
+	)
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			out.WriteString(s)
+			break
+		}
+		out.WriteString(s[:i])
+		s = s[i:]
+		switch {
+		case strings.HasPrefix(s, "$$"):
+			out.WriteString("$$")
+			s = s[2:]
+		case strings.HasPrefix(s, fileRefPrefix):
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return "", false, errors.New("config: unterminated ${file:...} reference")
+			}
+			path := s[len(fileRefPrefix):end]
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", false, fmt.Errorf("config: ${file:%s}: %w", path, err)
+			}
+			out.WriteString(strings.ReplaceAll(strings.TrimSpace(string(data)), "$", "$$"))
+			found = true
+			s = s[end+1:]
+		default:
+			out.WriteByte('$')
+			s = s[1:]
+		}
+	}
+	return out.String(), found, nil
+}
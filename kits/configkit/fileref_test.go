@@ -0,0 +1,55 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRefs_ReadMountedSecrets(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	secret := filepath.Join(tmp, "db")
+	writeFile(t, secret, []byte("pa$$word\n"))
+	t.Setenv("DB_USER", "svc")
+	writeFile(t, filepath.Join("config", "config.yml"), []byte(
+		"db:\n  user: ${DB_USER}\n  password: \"${file:"+secret+"}\"\n  literal: $${file:/nope}\n"))
+
+	p, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "svc", p.Get("db.user").String())
+	require.Equal(t, "pa$$word", p.Get("db.password").String())
+	require.Equal(t, "${file:/nope}", p.Get("db.literal").String())
+
+	missing := filepath.Join(tmp, "missing")
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("db:\n  password: ${file:"+missing+"}\n"))
+	_, err = config.NewYAML(context.Background())
+	require.ErrorContains(t, err, "${file:"+missing+"}")
+}
+
+func TestFileRefs_OnlyScalarValuesAndMarkedSecret(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	conn := filepath.Join(tmp, "conn")
+	writeFile(t, conn, []byte("host: db # not yaml here\n"))
+	writeFile(t, filepath.Join("config", "config.yml"), []byte(
+		"# old: ${file:/nope}\nupstream:\n  conn: ${file:"+conn+"}\n  name: orders\n"))
+
+	p, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "host: db # not yaml here", p.Get("upstream.conn").String())
+
+	var raw any
+	require.NoError(t, p.Get("upstream").Populate(&raw))
+	require.Equal(t, map[string]any{"conn": "***", "name": "orders"}, config.Redact(p, "upstream", raw))
+}
@@ -0,0 +1,29 @@
+package configkit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	uber "go.uber.org/config"
+)
+
+// Fingerprint returns a stable SHA-256 hash (hex) of the whole config tree
+// after normalization and redaction. Equal configs hash equally regardless of
+// file layout or key order, so comparing fingerprints across restarts shows
+// whether a deploy changed config. Secret values are masked before hashing:
+// rotating a secret does not change the fingerprint.
+func Fingerprint(p Provider) (string, error) {
+	var raw any
+	if err := p.Get(uber.Root).Populate(&raw); err != nil {
+		return "", fmt.Errorf("config: fingerprint: %w", err)
+	}
+	// encoding/json writes map keys in sorted order.
+	b, err := json.Marshal(Redact(p, uber.Root, raw))
+	if err != nil {
+		return "", fmt.Errorf("config: fingerprint: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
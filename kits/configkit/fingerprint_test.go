@@ -0,0 +1,31 @@
+package configkit_test
+
+import (
+	"strings"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	uber "go.uber.org/config"
+)
+
+func TestFingerprint(t *testing.T) {
+	fp := func(yaml string) string {
+		t.Helper()
+		p, err := uber.NewYAML(uber.Source(strings.NewReader(yaml)))
+		require.NoError(t, err)
+		s, err := config.Fingerprint(p)
+		require.NoError(t, err)
+		return s
+	}
+
+	base := fp("http:\n  addr: :8080\n  timeout: 5s\ndb:\n  password: one\n")
+	require.Len(t, base, 64)
+
+	// Key order does not matter.
+	require.Equal(t, base, fp("db:\n  password: one\nhttp:\n  timeout: 5s\n  addr: :8080\n"))
+	// Secrets are redacted before hashing.
+	require.Equal(t, base, fp("http:\n  addr: :8080\n  timeout: 5s\ndb:\n  password: two\n"))
+	// Anything else changes the fingerprint.
+	require.NotEqual(t, base, fp("http:\n  addr: :9090\n  timeout: 5s\ndb:\n  password: one\n"))
+}
@@ -3,16 +3,17 @@
 package configkit
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/froppa/stackkit/kits/runtimeinfo"
 	"github.com/go-playground/validator/v10"
 	uber "go.uber.org/config"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // validate is a singleton instance of the validator used for all config structs.
@@ -29,22 +30,57 @@ var validate = validator.New()
 // 2. Base Config: `config/config.yml`
 // 3. Local Overrides: `config/config.local.yml`
 // 4. Service-Specific Overrides: `config/<service-name>.yml` (from the runtimeinfo package).
-// 5. Environment Variables: Any `${...}` placeholders are expanded.
+// 5. Mounted Directories: Added via `WithMountedDir()`.
+// 6. Secrets: Files added via `WithSecretsFile()`.
+// 7. Environment Variables: Any `${...}` placeholders are expanded.
+//
+// Each file in 2-4 may also exist as `.toml` or `.json` (JSON is a subset of
+// YAML). Variants of one file are layered TOML, then JSON, then YAML, so YAML
+// wins where several set the same key.
+//
+// The provider is available both as *uber.YAML and as Provider. With
+// WithWatch, a *ReloadNotifier is provided as well. configkit's log output
+// (the loaded config's fingerprint, warnings) goes to the app's *zap.Logger
+// when one is provided; see SetLogger.
 func Module(opts ...ModuleOption) fx.Option {
 	var cfg moduleOpts
 	for _, opt := range opts {
 		opt(&cfg)
 	}
-	return fx.Provide(func() (*uber.YAML, error) {
-		return load(cfg.extra...)
-	})
+	provide := fx.Options(
+		fx.Provide(
+			func() (*uber.YAML, error) {
+				return cfg.load()
+			},
+			func(p *uber.YAML) Provider { return p },
+		),
+		fx.Invoke(useAppLogger),
+	)
+	if !cfg.watch {
+		return provide
+	}
+	return fx.Options(provide,
+		fx.Provide(cfg.newReloadNotifier),
+		// Start the watcher even when nothing asks for the notifier.
+		fx.Invoke(func(*ReloadNotifier) {}),
+	)
+}
+
+// Load builds the same provider as Module, outside of Fx. Use it for settings
+// needed before the container exists, such as fx.Option values.
+func Load(opts ...ModuleOption) (*uber.YAML, error) {
+	var cfg moduleOpts
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg.load()
 }
 
 // Provide returns an Fx provider that loads the entire configuration into type T,
 // validates it, and provides a pointer to it (`*T`) to the Fx container.
 //
 // It is a convenient shorthand for `ProvideFromKey[T](uber.Root)`.
-func Provide[T any]() func(*uber.YAML) (*T, error) {
+func Provide[T any]() func(Provider) (*T, error) {
 	return ProvideFromKey[T](uber.Root)
 }
 
@@ -54,22 +90,108 @@ func Provide[T any]() func(*uber.YAML) (*T, error) {
 //
 // If validation fails based on the `validate` tags in the struct, the Fx
 // application will fail to start with a descriptive error.
-func ProvideFromKey[T any](key string) func(provider *uber.YAML) (*T, error) {
+func ProvideFromKey[T any](key string) func(provider Provider) (*T, error) {
 	// Register this requirement at construction time for discovery.
 	registerRequirementFor[T](key)
-	return func(provider *uber.YAML) (*T, error) {
-		var cfg T
-		if err := provider.Get(key).Populate(&cfg); err != nil {
-			return nil, fmt.Errorf("config: could not populate key %q into %T: %w", key, cfg, err)
-		}
+	return func(provider Provider) (*T, error) {
+		return populate[T](provider, key)
+	}
+}
 
-		// Automatically run struct validation after populating.
-		if err := validate.Struct(&cfg); err != nil {
-			return nil, fmt.Errorf("config: validation failed for key %q (%T): %w", key, cfg, err)
-		}
+// populate loads, checks for deprecations, and validates the subtree at key.
+func populate[T any](provider Provider, key string) (*T, error) {
+	var cfg T
+	if err := populateInto(provider, key, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// populateInto is populate over the value cfg already holds: only fields
+// present in the configuration overwrite it.
+func populateInto[T any](provider Provider, key string, cfg *T) error {
+	t := reflect.TypeOf(cfg).Elem()
+	// Strict checks run first: they report every mismatch, where decoding
+	// stops at the first one it cannot coerce.
+	if issues := strictTypeIssues(provider, key, t); len(issues) > 0 {
+		return strictTypesError(key, issues)
+	}
+	if err := unknownKeysError(provider, key, t); err != nil {
+		return err
+	}
+	if err := listValue(provider, key, t).Populate(cfg); err != nil {
+		return fmt.Errorf("config: could not populate key %q into %T: %w", key, *cfg, err)
+	}
+
+	// Warn about deprecated fields that are still set.
+	var raw any
+	if err := provider.Get(key).Populate(&raw); err == nil {
+		warnDeprecated(key, raw, t)
+	}
 
-		return &cfg, nil
+	// Fill `default:` tags, then run struct validation.
+	if err := applyDefaults(reflect.ValueOf(cfg), key, rawSubtree(provider, key)); err != nil {
+		return err
 	}
+	foldCase(reflect.ValueOf(cfg))
+	if err := validate.Struct(cfg); err != nil {
+		return newValidationError(key, *cfg, err)
+	}
+	return nil
+}
+
+// validationError reports every failed rule of a subtree with its full dotted
+// path. It unwraps to the validator error.
+type validationError struct {
+	key    string
+	typ    string
+	issues []string
+	err    error
+}
+
+func newValidationError(key string, cfg any, err error) *validationError {
+	issues := formatValidationIssues(err, reflect.TypeOf(cfg))
+	for i, issue := range issues {
+		issues[i] = joinKey(key, issue)
+	}
+	return &validationError{key: key, typ: fmt.Sprintf("%T", cfg), issues: issues, err: err}
+}
+
+func (e *validationError) Error() string {
+	return fmt.Sprintf("config: validation failed for key %q (%s): %s", e.key, e.typ, strings.Join(e.issues, "; "))
+}
+
+func (e *validationError) Unwrap() error { return e.err }
+
+// PopulateWithDefaults loads the subtree at key over a deep copy of defaults
+// and validates the result. Only fields present in the configuration override
+// the defaults, so nested defaults can be written as a plain Go value instead
+// of `default:` tags. defaults itself is never modified.
+func PopulateWithDefaults[T any](p Provider, key string, defaults T) (*T, error) {
+	cfg := deepCopy(defaults)
+	if err := populateInto(p, key, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// GetMap returns the subtree at key as a generic map with string keys at every
+// level, for dynamic config (feature flags, plugins) that has no fixed struct.
+// A missing key yields an empty map; a key holding a non-map value is an error.
+func GetMap(p Provider, key string) (map[string]any, error) {
+	v := p.Get(key)
+	if !v.HasValue() {
+		return map[string]any{}, nil
+	}
+	var raw any
+	if err := v.Populate(&raw); err != nil {
+		return nil, fmt.Errorf("config: could not populate key %q: %w", key, err)
+	}
+	m, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("config: key %q is %T, not a map", key, raw)
+	}
+	return m, nil
 }
 
 // ModuleOption customizes the behavior of the config Module by adding extra sources.
@@ -79,59 +201,191 @@ type ModuleOption func(*moduleOpts)
 // This is useful for providing default configurations from code.
 func WithSources(srcs ...uber.YAMLOption) ModuleOption {
 	return func(o *moduleOpts) {
-		o.extra = append(o.extra, srcs...)
+		for _, src := range srcs {
+			o.extra = append(o.extra, layer{name: fmt.Sprintf("source[%d]", len(o.extra)), src: src})
+		}
 	}
 }
 
 // WithEmbeddedBytes adds an embedded YAML payload (e.g., from `//go:embed`) as a
 // low-precedence source for default values.
 func WithEmbeddedBytes(b []byte) ModuleOption {
-	return WithSources(uber.Source(bytes.NewReader(b)))
+	return func(o *moduleOpts) {
+		src, secrets := yamlSource(b)
+		o.extra = append(o.extra, layer{name: embeddedLayerName, src: src, secrets: secrets})
+	}
 }
 
 // --- Internal Implementation ---
 
 type moduleOpts struct {
-	extra []uber.YAMLOption
+	extra        []layer
+	mounted      []string
+	secrets      []string
+	permWarnings bool
+	strictTypes  bool
+	strictKeys   bool
+
+	overrideReport bool
+	watch          bool
+
+	runtimeCheck  bool
+	runtimeStrict bool
+}
+
+// layer is a single named source in the precedence chain. Names are file paths
+// for file-based sources and short markers (e.g. "embedded", "env") otherwise.
+type layer struct {
+	name    string
+	path    string // set for file-based sources only
+	secret  bool   // loaded via WithSecretsFile
+	src     uber.YAMLOption
+	secrets []string // dotted paths Redact masks; see secretsOf
+}
+
+// fileLayer returns a layer reading the YAML (or, by extension, TOML) file at
+// path.
+func fileLayer(path string) layer {
+	if isTOML(path) {
+		return tomlFileLayer(path)
+	}
+	src, secrets := yamlFileSource(path)
+	return layer{name: path, path: path, src: src, secrets: secrets}
+}
+
+const (
+	envLayerName      = "env"
+	embeddedLayerName = "embedded"
+)
+
+// envLayer expands `${VAR:default}` placeholders. It is always applied last.
+func envLayer() layer {
+	return layer{name: envLayerName, src: uber.Expand(os.LookupEnv)}
+}
+
+func (o moduleOpts) load() (*uber.YAML, error) {
+	if err := o.checkRuntimeInfo(); err != nil {
+		return nil, err
+	}
+	mounted, err := mountedLayers(o.mounted)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := secretLayers(o.secrets)
+	if err != nil {
+		return nil, err
+	}
+	p, err := load(o.extra, mounted, secrets)
+	if err != nil {
+		return nil, err
+	}
+	if o.permWarnings {
+		chain, _ := chainFor(p)
+		warnPermissions(chain)
+	}
+	rememberStrict(p, o.strictness())
+	if o.overrideReport {
+		logOverrides(p)
+	}
+	if fp, err := Fingerprint(p); err == nil {
+		logger.Load().Info("config: loaded", zap.String("fingerprint", fp))
+	}
+	return p, nil
 }
 
 // load builds the layered uber/config provider from all available sources.
-func load(extra ...uber.YAMLOption) (*uber.YAML, error) {
+func load(extra, mounted, secrets []layer) (*uber.YAML, error) {
 	// Pre-allocate slice with a reasonable capacity.
-	opts := make([]uber.YAMLOption, 0, len(extra)+4)
+	chain := make([]layer, 0, len(extra)+len(mounted)+len(secrets)+4)
 
 	// Custom sources have the lowest precedence.
-	opts = append(opts, extra...)
+	chain = append(chain, extra...)
 
 	// File-based sources are layered on top.
-	opts = append(opts, fileOptions("config")...)
+	chain = append(chain, fileLayers("config")...)
+
+	// Mounted directories override config files.
+	chain = append(chain, mounted...)
+
+	// Secrets files override regular config files.
+	chain = append(chain, secrets...)
 
 	// Environment variable expansion has the highest precedence.
-	opts = append(opts, uber.Expand(os.LookupEnv))
+	chain = append(chain, envLayer())
 
-	return uber.NewYAML(opts...)
+	return build(chain)
 }
 
-// fileOptions discovers and returns YAML options for standard config file locations.
-func fileOptions(dir string) []uber.YAMLOption {
-	// Standard configuration files to search for, in order of precedence.
-	files := []string{
-		filepath.Join(dir, "config.yml"),       // Base config
-		filepath.Join(dir, "config.local.yml"), // Local overrides
+// build constructs a provider from the chain and remembers the chain so the
+// provider can later be traced back to its sources.
+func build(chain []layer) (*uber.YAML, error) {
+	opts := make([]uber.YAMLOption, 0, len(chain))
+	for _, l := range chain {
+		opts = append(opts, l.src)
+	}
+	p, err := uber.NewYAML(opts...)
+	if err != nil {
+		return nil, sourceError(chain, err)
 	}
+	rememberChain(p, chain)
+	return p, nil
+}
 
-	// Add a service-specific override file if the service name is set via runtimeinfo.
-	// This allows for multi-service repos with shared base configs.
-	if name := strings.TrimSpace(runtimeinfo.Name); name != "" {
-		files = append(files, filepath.Join(dir, name+".yml"))
+// sourceError attributes a build failure to the first source that fails on
+// its own, since uber/config does not report which source it could not decode.
+//
+// Each source is decoded separately before layering, so YAML anchors resolve
+// within a single source only. An alias to an anchor from another source is
+// reported as such rather than as a bare "unknown anchor".
+func sourceError(chain []layer, err error) error {
+	for _, l := range chain {
+		if l.name == envLayerName {
+			continue
+		}
+		if _, lerr := uber.NewYAML(l.src); lerr != nil {
+			if strings.Contains(lerr.Error(), "unknown anchor") {
+				return fmt.Errorf("config: source %q: %w (anchors are resolved per source; aliases cannot reference anchors defined in another source)", l.name, lerr)
+			}
+			return fmt.Errorf("config: source %q: %w", l.name, lerr)
+		}
 	}
+	return err
+}
 
-	var opts []uber.YAMLOption
-	for _, path := range files {
+// configFiles returns the candidate paths for one config file in dir, lowest
+// precedence first: the TOML variant, the JSON one, then YAML.
+func configFiles(dir, base string) []string {
+	return []string{
+		filepath.Join(dir, base+".toml"),
+		filepath.Join(dir, base+".json"),
+		filepath.Join(dir, base+".yml"),
+	}
+}
+
+// fileLayers discovers and returns layers for standard config file locations.
+func fileLayers(dir string) []layer {
+	var out []layer
+	for _, path := range candidateFiles(dir) {
 		// Only include the file source if it exists and is a regular file.
 		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
-			opts = append(opts, uber.File(path))
+			out = append(out, fileLayer(path))
 		}
 	}
-	return opts
+	return out
+}
+
+// candidateFiles lists every standard config file in dir, lowest precedence
+// first, whether or not it exists.
+func candidateFiles(dir string) []string {
+	// Standard configuration files to search for, in order of precedence.
+	var files []string
+	files = append(files, configFiles(dir, "config")...)       // Base config
+	files = append(files, configFiles(dir, "config.local")...) // Local overrides
+
+	// Add a service-specific override file if the service name is set via runtimeinfo.
+	// This allows for multi-service repos with shared base configs.
+	if name := strings.TrimSpace(runtimeinfo.Name); name != "" {
+		files = append(files, configFiles(dir, name)...)
+	}
+	return files
 }
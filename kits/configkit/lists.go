@@ -0,0 +1,144 @@
+package configkit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	uber "go.uber.org/config"
+	"gopkg.in/yaml.v3"
+)
+
+// listValue returns the value at key with comma-separated strings expanded
+// into sequences wherever t expects a list of scalars, so that
+//
+//	origins: ${CORS_ORIGINS:https://a.example}
+//
+// fills an []string field from CORS_ORIGINS=https://a.example,https://b.example.
+// Items are trimmed and an empty string yields an empty list. Values that
+// need no rewriting are returned as read.
+func listValue(p Provider, key string, t reflect.Type) Value {
+	v := p.Get(key)
+	var raw any
+	if !v.HasValue() || v.Populate(&raw) != nil {
+		return v
+	}
+	fixed, changed := splitLists(normalize(raw), t)
+	if !changed {
+		return v
+	}
+	b, err := yaml.Marshal(fixed)
+	if err != nil {
+		return v
+	}
+	np, err := uber.NewYAML(uber.Source(bytes.NewReader(b)))
+	if err != nil {
+		return v
+	}
+	return np.Get(uber.Root)
+}
+
+// splitLists walks v alongside t and reports whether any string was split.
+func splitLists(v any, t reflect.Type) (any, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, false
+		}
+		changed := false
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("yaml") == "-" {
+				continue
+			}
+			name, inline := parseYAMLTag(f.Tag.Get("yaml"), f)
+			if inline {
+				if _, c := splitLists(m, f.Type); c {
+					changed = true
+				}
+				continue
+			}
+			if val, ok := m[name]; ok {
+				if nv, c := splitLists(val, f.Type); c {
+					m[name], changed = nv, true
+				}
+			}
+		}
+		return m, changed
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, false
+		}
+		changed := false
+		for k, val := range m {
+			if nv, c := splitLists(val, t.Elem()); c {
+				m[k], changed = nv, true
+			}
+		}
+		return m, changed
+	case reflect.Slice, reflect.Array:
+		switch s := v.(type) {
+		case nil, map[string]any:
+		case []any:
+			changed := false
+			for i, val := range s {
+				if nv, c := splitLists(val, t.Elem()); c {
+					s[i], changed = nv, true
+				}
+			}
+			return s, changed
+		case string:
+			if isScalarKind(t.Elem()) {
+				return splitItems(s, t.Elem()), true
+			}
+		default:
+			// A lone number or bool, e.g. the default in ${PORTS:80}.
+			if isScalarKind(t.Elem()) {
+				return []any{s}, true
+			}
+		}
+	}
+	return v, false
+}
+
+// splitItems splits s on commas. Items for non-string elements are decoded as
+// YAML scalars so that "80,443" fills an []int.
+func splitItems(s string, elem reflect.Type) []any {
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	out := []any{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var item any = part
+		if elem.Kind() != reflect.String {
+			if err := yaml.Unmarshal([]byte(part), &item); err != nil {
+				item = part
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func isScalarKind(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package configkit_test
+
+import (
+	"os"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideFromKey_ListFromEnv(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	type cors struct {
+		Origins []string `yaml:"origins" validate:"min=1,dive,url"`
+		Ports   []int    `yaml:"ports"`
+		Methods []string `yaml:"methods"`
+		Empty   []string `yaml:"empty"`
+	}
+	yml := []byte("cors:\n" +
+		"  origins: ${CORS_ORIGINS:https://a.example}\n" +
+		"  ports: ${CORS_PORTS:80}\n" +
+		"  methods: [GET, POST]\n" +
+		"  empty: ${CORS_EMPTY:\"\"}\n")
+
+	p, err := config.Load(config.WithEmbeddedBytes(yml))
+	require.NoError(t, err)
+	got, err := config.ProvideFromKey[cors]("cors")(p)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://a.example"}, got.Origins)
+	require.Equal(t, []int{80}, got.Ports)
+
+	t.Setenv("CORS_ORIGINS", "https://a.example, https://b.example")
+	t.Setenv("CORS_PORTS", "80,443")
+	p, err = config.Load(config.WithEmbeddedBytes(yml))
+	require.NoError(t, err)
+	got, err = config.ProvideFromKey[cors]("cors")(p)
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://a.example", "https://b.example"}, got.Origins)
+	require.Equal(t, []int{80, 443}, got.Ports)
+	require.Equal(t, []string{"GET", "POST"}, got.Methods)
+	require.Empty(t, got.Empty)
+}
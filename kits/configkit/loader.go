@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	uber "go.uber.org/config"
 )
@@ -17,21 +16,39 @@ type YAMLProvider = uber.YAML
 // Source is an alias for uber/config YAML options (file, reader, expand, etc.).
 type Source = uber.YAMLOption
 
-// File returns a Source that loads YAML from the given path.
-func File(path string) Source { return uber.File(path) }
+// File returns a Source that loads YAML from the given path, or TOML when the
+// path ends in ".toml". The file is read as is: unlike config files loaded by
+// Module, NewYAML, or WithFile, `${file:...}` references are not resolved.
+func File(path string) Source {
+	if isTOML(path) {
+		return tomlFileLayer(path).src
+	}
+	return uber.File(path)
+}
+
+// WithFile adds the YAML file at path as a source. Unlike WithSources(File(path)),
+// the path is kept as the source name, so Trace can report where values came from.
+func WithFile(path string) ModuleOption {
+	return func(o *moduleOpts) {
+		o.extra = append(o.extra, fileLayer(path))
+	}
+}
 
 // DefaultSources returns the default, low-precedence sources for CLI usage.
 // Precedence (lowest -> highest) when combined by NewYAML:
-//  1. Default file: config/config.yml (if present)
+//  1. Default files: config/config.toml, config/config.json, then
+//     config/config.yml (if present)
 //  2. Env override: CONFIG=file.yml (if set, must exist)
 //  3. CLI flag: passed via opts (highest precedence)
 //
 // Note: Services should continue using Module(); DefaultSources is intended for CLIs.
 func DefaultSources() []Source {
 	var out []Source
-	// Default file (if present)
-	if fi, err := os.Stat(filepath.Join("config", "config.yml")); err == nil && !fi.IsDir() {
-		out = append(out, uber.File(filepath.Join("config", "config.yml")))
+	// Default files (if present): TOML, then JSON, then YAML.
+	for _, path := range configFiles("config", "config") {
+		if isFile(path) {
+			out = append(out, fileLayer(path).src)
+		}
 	}
 	return out
 }
@@ -39,7 +56,7 @@ func DefaultSources() []Source {
 // NewYAML builds a YAML provider using the same underlying primitives as Module,
 // but with a CLI-friendly precedence model:
 //
-//	default config file -> $CONFIG override -> explicit sources via opts (highest)
+//	default config file -> $CONFIG override -> explicit sources via opts -> secrets files (highest)
 //
 // Environment expansion is always applied.
 // If $CONFIG is set but the file is missing, an error is returned.
@@ -49,19 +66,25 @@ func NewYAML(_ context.Context, opts ...ModuleOption) (*YAMLProvider, error) {
 	for _, opt := range opts {
 		opt(&o)
 	}
+	if err := o.checkRuntimeInfo(); err != nil {
+		return nil, err
+	}
 
 	// Build precedence stack.
-	// Start with default on-disk file if present.
-	chain := make([]uber.YAMLOption, 0, 4)
-	chain = append(chain, DefaultSources()...)
+	// Start with default on-disk files if present; YAML above JSON above TOML.
+	chain := make([]layer, 0, 5)
+	for _, path := range configFiles("config", "config") {
+		if isFile(path) {
+			chain = append(chain, fileLayer(path))
+		}
+	}
 
 	// Env CONFIG override (must exist if set)
 	if cfgPath, ok := os.LookupEnv("CONFIG"); ok {
-		if fi, err := os.Stat(cfgPath); err == nil && !fi.IsDir() {
-			chain = append(chain, uber.File(cfgPath))
-		} else {
+		if !isFile(cfgPath) {
 			return nil, fmt.Errorf("config: CONFIG path %q not found or not a file", cfgPath)
 		}
+		chain = append(chain, fileLayer(cfgPath))
 	}
 
 	// CLI-provided sources (highest precedence for CLIs)
@@ -69,12 +92,43 @@ func NewYAML(_ context.Context, opts ...ModuleOption) (*YAMLProvider, error) {
 		chain = append(chain, o.extra...)
 	}
 
+	// Mounted directories override files.
+	mounted, err := mountedLayers(o.mounted)
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain, mounted...)
+
+	// Secrets files sit above everything but env expansion.
+	secrets, err := secretLayers(o.secrets)
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain, secrets...)
+
 	// Always expand environment variables.
-	chain = append(chain, uber.Expand(os.LookupEnv))
+	chain = append(chain, envLayer())
 
 	// Build provider.
 	if len(chain) == 0 {
 		return nil, errors.New("config: no configuration sources available")
 	}
-	return uber.NewYAML(chain...)
+	p, err := build(chain)
+	if err != nil {
+		return nil, err
+	}
+	if o.permWarnings {
+		warnPermissions(chain)
+	}
+	rememberStrict(p, o.strictness())
+	if o.overrideReport {
+		logOverrides(p)
+	}
+	return p, nil
+}
+
+// isFile reports whether path exists and is a regular file.
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
 }
@@ -0,0 +1,102 @@
+package configkit
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxPendingEntries bounds how many entries are held back until SetLogger is
+// called.
+const maxPendingEntries = 100
+
+// logger receives what configkit logs while loading and reloading
+// configuration: the fingerprint, deprecated fields, permission and runtime
+// info warnings, reloads. Until SetLogger is called, the first
+// maxPendingEntries entries are held back and replayed to the logger it is
+// given, so Module can hand over the app's logger after loading.
+var logger atomic.Pointer[zap.Logger]
+
+var pending = &pendingLog{}
+
+func init() { SetLogger(nil) }
+
+// SetLogger routes configkit's log output to l, first replaying the entries
+// held back so far. Module calls it with the application's *zap.Logger, when
+// there is one. Passing nil drops the held-back entries and restores the
+// default of holding entries back.
+func SetLogger(l *zap.Logger) {
+	if l == nil {
+		pending.replay(zapcore.NewNopCore())
+		logger.Store(zap.New(pendingCore{log: pending}))
+		return
+	}
+	logger.Store(l)
+	pending.replay(l.Core())
+}
+
+type loggerParams struct {
+	fx.In
+	Log *zap.Logger `optional:"true"`
+}
+
+// useAppLogger hands the application's logger, if any, to SetLogger.
+func useAppLogger(p loggerParams) {
+	if p.Log != nil {
+		SetLogger(p.Log)
+	}
+}
+
+// pendingLog holds entries logged before SetLogger is called.
+type pendingLog struct {
+	mu      sync.Mutex
+	entries []pendingEntry
+}
+
+type pendingEntry struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+func (p *pendingLog) replay(core zapcore.Core) {
+	p.mu.Lock()
+	entries := p.entries
+	p.entries = nil
+	p.mu.Unlock()
+	for _, e := range entries {
+		if ce := core.Check(e.ent, nil); ce != nil {
+			ce.Write(e.fields...)
+		}
+	}
+}
+
+// pendingCore records entries into a pendingLog.
+type pendingCore struct {
+	log    *pendingLog
+	fields []zapcore.Field
+}
+
+func (c pendingCore) Enabled(zapcore.Level) bool { return true }
+
+func (c pendingCore) With(fields []zapcore.Field) zapcore.Core {
+	return pendingCore{log: c.log, fields: slices.Concat(c.fields, fields)}
+}
+
+func (c pendingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c pendingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.log.mu.Lock()
+	defer c.log.mu.Unlock()
+	if len(c.log.entries) < maxPendingEntries {
+		c.log.entries = append(c.log.entries, pendingEntry{ent: ent, fields: slices.Concat(c.fields, fields)})
+	}
+	return nil
+}
+
+func (c pendingCore) Sync() error { return nil }
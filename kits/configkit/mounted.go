@@ -0,0 +1,126 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	uber "go.uber.org/config"
+	"gopkg.in/yaml.v3"
+)
+
+// WithMountedDir layers a Kubernetes ConfigMap-style directory: each regular
+// file is one key named after the file, holding the file's trimmed contents.
+// Dots in file names nest keys, so a file `http.addr` sets http.addr. Values
+// are parsed as YAML scalars (`8080` is an int, `true` a bool); anything else
+// is kept as a string.
+//
+// Mounted directories override regular config files and sit below secrets
+// files and environment expansion. Hidden entries, such as the `..data`
+// links Kubernetes uses for atomic updates, are skipped.
+func WithMountedDir(path string) ModuleOption {
+	return func(o *moduleOpts) {
+		o.mounted = append(o.mounted, path)
+	}
+}
+
+// mountedLayers reads each mounted directory into a layer.
+func mountedLayers(dirs []string) ([]layer, error) {
+	out := make([]layer, 0, len(dirs))
+	for _, dir := range dirs {
+		tree, err := readMountedDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("config: mounted dir %q: %w", dir, err)
+		}
+		out = append(out, layer{name: dir, src: uber.Static(tree)})
+	}
+	return out, nil
+}
+
+func readMountedDir(dir string) (map[string]any, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	tree := map[string]any{}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		// Stat follows the symlinks ConfigMap volumes are made of.
+		path := filepath.Join(dir, e.Name())
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := setMounted(tree, e.Name(), mountedValue(strings.TrimSpace(string(b)))); err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
+
+// mountedValue decodes s as a YAML scalar, falling back to the raw string for
+// documents, multi-line text, and anything that is not a scalar. Mounted
+// values are literal: `${VAR}` expansion runs over them too, so `$` in a
+// string is escaped as `$$`.
+func mountedValue(s string) any {
+	var v any
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil || v == nil {
+		return escapeDollar(s)
+	}
+	switch t := v.(type) {
+	case map[string]any, []any:
+		return escapeDollar(s)
+	case string:
+		return escapeDollar(t)
+	}
+	return v
+}
+
+func escapeDollar(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+// setMounted stores v under the dotted key, creating intermediate maps.
+func setMounted(tree map[string]any, key string, v any) error {
+	parts := strings.Split(key, ".")
+	m := tree
+	for i, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return fmt.Errorf("file %q: empty key segment", key)
+		}
+		next, ok := m[part]
+		if !ok {
+			child := map[string]any{}
+			m[part], m = child, child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("file %q: %q is already a value", key, strings.Join(parts[:i+1], "."))
+		}
+		m = child
+	}
+	last := parts[len(parts)-1]
+	if last == "" {
+		return fmt.Errorf("file %q: empty key segment", key)
+	}
+	if prev, ok := m[last]; ok {
+		kind := "a value"
+		if _, isMap := prev.(map[string]any); isMap {
+			kind = "a subtree"
+		}
+		return fmt.Errorf("file %q: key %q conflicts with %s set by another file", key, key, kind)
+	}
+	m[last] = v
+	return nil
+}
@@ -0,0 +1,63 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMountedDir(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("http:\n  addr: \":80\"\n  port: 80\nname: base\n"))
+
+	// ConfigMap volumes hold the data in a hidden directory and link to it.
+	dir := filepath.Join(tmp, "mounted")
+	writeFile(t, filepath.Join(dir, "..data", "http.addr"), []byte(":8080\n"))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "http.addr"), filepath.Join(dir, "http.addr")))
+	writeFile(t, filepath.Join(dir, "http.port"), []byte("8080"))
+	writeFile(t, filepath.Join(dir, "debug"), []byte("true\n"))
+	writeFile(t, filepath.Join(dir, "banner"), []byte("line one\nline two: x\n"))
+	writeFile(t, filepath.Join(dir, "db.password"), []byte("pa$$word${HOME}$\n"))
+
+	p, err := config.NewYAML(context.Background(), config.WithMountedDir(dir))
+	require.NoError(t, err)
+
+	var cfg struct {
+		HTTP struct {
+			Addr string `yaml:"addr"`
+			Port int    `yaml:"port"`
+		} `yaml:"http"`
+		Name   string `yaml:"name"`
+		Debug  bool   `yaml:"debug"`
+		Banner string `yaml:"banner"`
+		DB     struct {
+			Password string `yaml:"password"`
+		} `yaml:"db"`
+	}
+	require.NoError(t, p.Get("").Populate(&cfg))
+	require.Equal(t, ":8080", cfg.HTTP.Addr)
+	require.Equal(t, 8080, cfg.HTTP.Port)
+	require.Equal(t, "base", cfg.Name)
+	require.True(t, cfg.Debug)
+	require.Equal(t, "line one\nline two: x", cfg.Banner)
+	require.Equal(t, "pa$$word${HOME}$", cfg.DB.Password, "mounted values are not env-expanded")
+}
+
+func TestWithMountedDir_Errors(t *testing.T) {
+	_, err := config.NewYAML(context.Background(), config.WithMountedDir(filepath.Join(t.TempDir(), "missing")))
+	require.ErrorContains(t, err, "mounted dir")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "http"), []byte("on"))
+	writeFile(t, filepath.Join(dir, "http.addr"), []byte(":8080"))
+	_, err = config.NewYAML(context.Background(), config.WithMountedDir(dir))
+	require.ErrorContains(t, err, `"http" is already a value`)
+}
@@ -0,0 +1,75 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	uber "go.uber.org/config"
+	"go.uber.org/zap"
+)
+
+// WithPermissionWarnings logs a warning for every secrets file, and every
+// config file containing secret-looking keys (password, token, ...), whose
+// mode grants any access to group or other users, i.e. anything looser than
+// 0600. Loading is not affected. Warnings go to the app's *zap.Logger under
+// Module, or to the logger set by SetLogger otherwise.
+// The check is skipped on Windows, where Unix modes are not meaningful.
+func WithPermissionWarnings() ModuleOption {
+	return func(o *moduleOpts) {
+		o.permWarnings = true
+	}
+}
+
+// warnPermissions checks the file-based layers of chain.
+func warnPermissions(chain []layer) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	for _, l := range chain {
+		if l.path == "" {
+			continue
+		}
+		fi, err := os.Stat(l.path)
+		if err != nil {
+			continue
+		}
+		mode := fi.Mode().Perm()
+		if mode&0o077 == 0 {
+			continue
+		}
+		if !l.secret && !hasSecretKeys(l) {
+			continue
+		}
+		logger.Load().Warn("config: file with secrets is readable by group or others",
+			zap.String("path", l.path),
+			zap.String("mode", fmt.Sprintf("%04o", mode)),
+		)
+	}
+}
+
+// hasSecretKeys reports whether any key in the layer looks like a secret.
+func hasSecretKeys(l layer) bool {
+	p, err := uber.NewYAML(l.src)
+	if err != nil {
+		return false
+	}
+	var raw any
+	if err := p.Get(uber.Root).Populate(&raw); err != nil {
+		return false
+	}
+	return containsSecretKey(normalize(raw))
+}
+
+func containsSecretKey(v any) bool {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	for k, val := range m {
+		if isSecretKey(k) || containsSecretKey(val) {
+			return true
+		}
+	}
+	return false
+}
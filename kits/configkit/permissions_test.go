@@ -0,0 +1,64 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithPermissionWarnings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix modes only")
+	}
+	core, logs := observer.New(zapcore.WarnLevel)
+	config.SetLogger(zap.New(core))
+	t.Cleanup(func() { config.SetLogger(nil) })
+
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	// Group-readable, but nothing secret in it: no warning.
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("http:\n  addr: \":8080\"\n"))
+	require.NoError(t, os.Chmod(filepath.Join("config", "config.yml"), 0o644))
+
+	// Group-readable with a secret-looking key.
+	db := filepath.Join(tmp, "db.yml")
+	writeFile(t, db, []byte("db:\n  password: hunter2\n"))
+	require.NoError(t, os.Chmod(db, 0o640))
+
+	// Secrets file that is fine for WithSecretsFile but not 0600.
+	sec := filepath.Join(tmp, "secrets.yml")
+	writeFile(t, sec, []byte("api:\n  key: abc\n"))
+	require.NoError(t, os.Chmod(sec, 0o640))
+
+	// Private file with secrets: no warning.
+	priv := filepath.Join(tmp, "priv.yml")
+	writeFile(t, priv, []byte("token: abc\n"))
+	require.NoError(t, os.Chmod(priv, 0o600))
+
+	_, err := config.NewYAML(context.Background(),
+		config.WithFile(db), config.WithFile(priv), config.WithSecretsFile(sec))
+	require.NoError(t, err)
+	require.Zero(t, logs.Len(), "warnings are opt-in")
+
+	_, err = config.NewYAML(context.Background(),
+		config.WithFile(db), config.WithFile(priv), config.WithSecretsFile(sec),
+		config.WithPermissionWarnings())
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("config: file with secrets is readable by group or others").All()
+	require.Len(t, entries, 2)
+	require.Equal(t, db, entries[0].ContextMap()["path"])
+	require.Equal(t, "0640", entries[0].ContextMap()["mode"])
+	require.Equal(t, sec, entries[1].ContextMap()["path"])
+}
@@ -0,0 +1,18 @@
+package configkit
+
+import uber "go.uber.org/config"
+
+// Value is a configuration subtree returned by Provider.Get.
+type Value = uber.Value
+
+// Provider is the read-only view of configuration that ProvideFromKey, Check,
+// and friends need. *YAMLProvider satisfies it; tests and alternative backends
+// can supply their own implementation.
+//
+// Features that depend on how a provider was layered (Trace, Files) still
+// require a *YAMLProvider built by configkit.
+type Provider interface {
+	Get(key string) Value
+}
+
+var _ Provider = (*YAMLProvider)(nil)
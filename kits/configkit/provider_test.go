@@ -0,0 +1,85 @@
+package configkit_test
+
+import (
+	"reflect"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	uber "go.uber.org/config"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// tenantProvider serves keys from a per-tenant subtree, standing in for a
+// non-file backend.
+type tenantProvider struct {
+	p      *uber.YAML
+	tenant string
+}
+
+func (t tenantProvider) Get(key string) config.Value {
+	if key == uber.Root {
+		return t.p.Get(t.tenant)
+	}
+	return t.p.Get(t.tenant + "." + key)
+}
+
+type svcCfg struct {
+	Addr string `yaml:"addr" validate:"required"`
+}
+
+func TestProvider_CustomImplementation(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+
+	p := tenantProvider{
+		p:      providerFromYAML(t, "acme:\n  svc:\n    addr: \":8080\"\nother:\n  svc: {}\n"),
+		tenant: "acme",
+	}
+
+	var got *svcCfg
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(p, fx.As(new(config.Provider)))),
+		fx.Provide(config.ProvideFromKey[svcCfg]("svc")),
+		fx.Populate(&got),
+	)
+	app.RequireStart().RequireStop()
+	require.Equal(t, ":8080", got.Addr)
+
+	res := config.Check(p)
+	require.Len(t, res, 1)
+	require.True(t, res[0].OK)
+
+	p.tenant = "other"
+	res = config.Check(p)
+	require.False(t, res[0].OK)
+}
+
+func TestModule_ProvidesProviderInterface(t *testing.T) {
+	var (
+		yaml *uber.YAML
+		prov config.Provider
+	)
+	app := fxtest.New(t,
+		config.Module(config.WithEmbeddedBytes([]byte("a: 1\n"))),
+		fx.Populate(&yaml, &prov),
+	)
+	app.RequireStart().RequireStop()
+	require.Same(t, yaml, prov)
+}
+
+func TestCheckType_ValidatesRuntimeType(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	p := providerFromYAML(t, "http:\n  addr: \"\"\n  extra: 1\ndb:\n  host: a\n")
+
+	res := config.CheckType(p, "http", reflect.TypeOf(&registryHTTP{}))
+	require.False(t, res.OK)
+	require.Equal(t, "configkit_test.registryHTTP", res.Type)
+	require.Error(t, res.Err)
+	require.Equal(t, []string{"extra"}, res.Unknown)
+
+	res = config.CheckType(p, "db", reflect.TypeOf(registryDB{}))
+	require.True(t, res.OK, "%+v", res)
+	require.Empty(t, config.Requirements(), "CheckType must not register requirements")
+}
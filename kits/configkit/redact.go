@@ -2,33 +2,173 @@ package configkit
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
 )
 
-var secretWords = []string{"password", "secret", "token", "apikey", "key", "dsn", "cookie", "bearer"}
+var defaultSecretWords = []string{"password", "secret", "token", "apikey", "key", "dsn", "cookie", "bearer"}
+
+// secretWords holds the words in effect; see SetRedactWords.
+var secretWords atomic.Pointer[[]string]
+
+func init() { SetRedactWords() }
+
+// DefaultRedactWords returns a copy of the built-in redaction words.
+func DefaultRedactWords() []string {
+	return slices.Clone(defaultSecretWords)
+}
+
+// SetRedactWords replaces the words Redact, Summary, Diff, and the other
+// redacting helpers look for in key names. To extend the defaults, pass
+// append(DefaultRedactWords(), "credential", ...). Calling it with no words
+// restores the defaults.
+func SetRedactWords(words ...string) {
+	if len(words) == 0 {
+		words = defaultSecretWords
+	}
+	low := make([]string, len(words))
+	for i, w := range words {
+		low[i] = strings.ToLower(w)
+	}
+	secretWords.Store(&low)
+}
 
 // Redact masks secret-looking values within v for safe logging/display.
-// The key parameter can be used for future, key-specific redaction nuances.
-func Redact(_ string, v any) any {
-	return redact(normalize(v))
+// v was read from p at the dotted key (uber.Root for the whole tree); values
+// p loaded via WithSecretsFile or `${file:...}` are masked whatever their
+// name. p may be nil, leaving only the key-name heuristic.
+func Redact(p Provider, key string, v any) any {
+	out, _ := RedactWithReport(p, key, v)
+	return out
+}
+
+// RedactWithReport is like Redact and also returns the sorted, deduplicated
+// dotted paths (prefixed with key) of the values it masked, for auditing
+// which keys the redaction rules caught. Elements of lists share the path of
+// the list.
+func RedactWithReport(p Provider, key string, v any) (any, []string) {
+	secrets := secretsOf(p)
+	if key != "" && secrets.has(key) {
+		return "***", []string{key}
+	}
+	var masked []string
+	out := redact(*secretWords.Load(), secrets, key, normalize(v), &masked)
+	slices.Sort(masked)
+	return out, slices.Compact(masked)
+}
+
+// RedactWith is like Redact for a whole tree, but masks keys containing any
+// of words instead of the configured list. Matching is case-insensitive.
+func RedactWith(words []string, v any) any {
+	low := make([]string, len(words))
+	for i, w := range words {
+		low[i] = strings.ToLower(w)
+	}
+	return redact(low, nil, "", normalize(v), nil)
 }
 
-func redact(v any) any {
+// RedactStruct masks the fields of v, the config subtree for req read from p,
+// whose Go type is tagged `secret:"true"`. When the type of req is known
+// (registered as a requirement, via RegisterSpec, or via RegisterKnown) only
+// tagged fields and values p loaded as secrets (see Redact) are masked, so a
+// field merely named like a secret stays visible. Otherwise it falls back to
+// Redact's key-name heuristic.
+func RedactStruct(p Provider, req Requirement, v any) any {
+	fields, err := Spec(req)
+	if err != nil {
+		t, ok := KnownType(req.Key)
+		if !ok || t.Name() != trimPkg(req.Type) || t.PkgPath() != req.PkgPath {
+			return Redact(p, req.Key, v)
+		}
+		walkStruct(t, "", &fields)
+	}
+	secrets := secretsOf(p)
+	if req.Key != "" && secrets.has(req.Key) {
+		return "***"
+	}
+	var tagged [][]string
+	for _, f := range fields {
+		if f.Secret {
+			tagged = append(tagged, taggedPattern(f.Path))
+		}
+	}
+	return redactTagged(tagged, secrets, req.Key, "", normalize(v))
+}
+
+// taggedPattern turns the spec path of a secret field into the segments
+// redactTagged matches: list suffixes are dropped, as list elements share the
+// path of the list, and trailing map keys are trimmed so the whole map is
+// masked. Remaining "<key>" segments match any key.
+func taggedPattern(path string) []string {
+	parts := strings.Split(strings.ReplaceAll(path, listSuffix, ""), ".")
+	for len(parts) > 1 && parts[len(parts)-1] == mapKeyPlaceholder {
+		parts = parts[:len(parts)-1]
+	}
+	return parts
+}
+
+// isTagged reports whether path matches one of the tagged patterns.
+func isTagged(tagged [][]string, path string) bool {
+	parts := strings.Split(path, ".")
+	for _, pat := range tagged {
+		if len(pat) == len(parts) && slices.EqualFunc(pat, parts, func(p, s string) bool {
+			return p == mapKeyPlaceholder || p == s
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactTagged masks the values, whole subtrees included, at the paths
+// (relative to key) matching tagged.
+func redactTagged(tagged [][]string, secrets secretSet, key, rel string, v any) any {
 	switch t := v.(type) {
 	case map[string]any:
 		out := make(map[string]any, len(t))
 		for k, val := range t {
-			if isSecretKey(k) {
+			path := joinKey(rel, k)
+			if isTagged(tagged, path) || secrets.has(joinKey(key, path)) {
 				out[k] = "***"
 				continue
 			}
-			out[k] = redact(val)
+			out[k] = redactTagged(tagged, secrets, key, path, val)
 		}
 		return out
 	case []any:
 		out := make([]any, len(t))
 		for i, val := range t {
-			out[i] = redact(val)
+			out[i] = redactTagged(tagged, secrets, key, rel, val)
+		}
+		return out
+	default:
+		return t
+	}
+}
+
+// redact masks secret keys below prefix, appending their paths to masked
+// unless it is nil.
+func redact(words []string, secrets secretSet, prefix string, v any, masked *[]string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			path := joinKey(prefix, k)
+			if hasSecretWord(words, k) || secrets.has(path) {
+				out[k] = "***"
+				if masked != nil {
+					*masked = append(*masked, path)
+				}
+				continue
+			}
+			out[k] = redact(words, secrets, path, val, masked)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = redact(words, secrets, prefix, val, masked)
 		}
 		return out
 	default:
@@ -37,8 +177,13 @@ func redact(v any) any {
 }
 
 func isSecretKey(k string) bool {
+	return hasSecretWord(*secretWords.Load(), k)
+}
+
+// hasSecretWord reports whether k contains any of the lower-case words.
+func hasSecretWord(words []string, k string) bool {
 	low := strings.ToLower(k)
-	for _, w := range secretWords {
+	for _, w := range words {
 		if strings.Contains(low, w) {
 			return true
 		}
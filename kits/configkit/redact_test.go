@@ -1,9 +1,13 @@
 package configkit_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRedactNested(t *testing.T) {
@@ -17,7 +21,7 @@ func TestRedactNested(t *testing.T) {
 		},
 	}
 
-	got := config.Redact("", raw).(map[string]any)
+	got := config.Redact(nil, "", raw).(map[string]any)
 	db := got["database"].(map[string]any)
 	if db["password"] != "***" {
 		t.Fatalf("expected password redacted, got %v", db["password"])
@@ -27,3 +31,164 @@ func TestRedactNested(t *testing.T) {
 		t.Fatalf("expected token redacted, got %v", api["token"])
 	}
 }
+
+func TestRedact_SecretsFileKeys(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("db:\n  host: localhost\n  conn: \"plain\"\n"))
+	secrets := filepath.Join(tmp, "secrets.yml")
+	writeFile(t, secrets, []byte("db:\n  conn: \"postgres://u:p@db/app\"\n"))
+	require.NoError(t, os.Chmod(secrets, 0o600))
+
+	before, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+	fpBefore, err := config.Fingerprint(before)
+	require.NoError(t, err)
+
+	p, err := config.NewYAML(context.Background(), config.WithSecretsFile(secrets))
+	require.NoError(t, err)
+	require.Equal(t, "postgres://u:p@db/app", p.Get("db.conn").String())
+
+	var raw any
+	require.NoError(t, p.Get("db").Populate(&raw))
+	got := config.Redact(p, "db", raw).(map[string]any)
+	require.Equal(t, "***", got["conn"], "secrets-file key must be masked despite its name")
+	require.Equal(t, "localhost", got["host"])
+
+	require.Equal(t, "***", config.Redact(p, "db.conn", "postgres://u:p@db/app"))
+
+	// The marks belong to p: a provider loaded without the secrets file, or
+	// none at all, does not mask the key.
+	plain, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, plain.Get("db").Populate(&raw))
+	require.Equal(t, "plain", config.Redact(plain, "db", raw).(map[string]any)["conn"])
+	require.Equal(t, "plain", config.Redact(nil, "db.conn", "plain"))
+
+	fpAfter, err := config.Fingerprint(plain)
+	require.NoError(t, err)
+	require.Equal(t, fpBefore, fpAfter, "fingerprint must not depend on other providers")
+}
+
+func TestWithSecretsFile_RejectsLoosePermissions(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	secrets := filepath.Join(tmp, "secrets.yml")
+	writeFile(t, secrets, []byte("token: abc\n"))
+	require.NoError(t, os.Chmod(secrets, 0o644))
+
+	_, err := config.NewYAML(context.Background(), config.WithSecretsFile(secrets))
+	require.ErrorContains(t, err, "accessible by others")
+
+	_, err = config.NewYAML(context.Background(), config.WithSecretsFile(filepath.Join(tmp, "missing.yml")))
+	require.Error(t, err)
+}
+
+func TestSummary_AllowlistedScalarsOnly(t *testing.T) {
+	p := providerFromYAML(t, `
+telemetry:
+  trace_sampler: parent_ratio
+  otlp_endpoint: collector:4317
+  resource_attributes: { team: a }
+db:
+  password: hunter2
+`)
+	got := config.Summary(p, []string{
+		"telemetry.trace_sampler",
+		"telemetry.otlp_endpoint",
+		"telemetry.resource_attributes", // map: skipped
+		"telemetry.missing",             // unset: skipped
+		"db.password",
+	})
+	require.Equal(t, map[string]string{
+		"telemetry.trace_sampler": "parent_ratio",
+		"telemetry.otlp_endpoint": "collector:4317",
+		"db.password":             "***",
+	}, got)
+}
+
+func TestRedactWords_ExtendAndReplace(t *testing.T) {
+	raw := map[string]any{"credential": "c", "Passphrase": "p", "password": "x", "user": "svc"}
+
+	got := config.Redact(nil, "", raw).(map[string]any)
+	require.Equal(t, "c", got["credential"], "defaults unchanged")
+
+	config.SetRedactWords(append(config.DefaultRedactWords(), "credential", "PASSPHRASE")...)
+	t.Cleanup(func() { config.SetRedactWords() })
+	got = config.Redact(nil, "", raw).(map[string]any)
+	require.Equal(t, map[string]any{"credential": "***", "Passphrase": "***", "password": "***", "user": "svc"}, got)
+
+	got = config.RedactWith([]string{"user"}, raw).(map[string]any)
+	require.Equal(t, map[string]any{"credential": "c", "Passphrase": "p", "password": "x", "user": "***"}, got)
+}
+
+type routingCfg struct {
+	Key    string `yaml:"key"`
+	Signer struct {
+		PEM string `yaml:"pem" secret:"true"`
+	} `yaml:"signer"`
+}
+
+func TestRedactStruct_UsesSecretTags(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterRequirement("routing", routingCfg{})
+	reqs := config.Requirements()
+	require.Len(t, reqs, 1)
+
+	raw := map[string]any{"key": "orders.eu", "signer": map[string]any{"pem": "-----BEGIN"}}
+	got := config.RedactStruct(nil, reqs[0], raw)
+	require.Equal(t, map[string]any{"key": "orders.eu", "signer": map[string]any{"pem": "***"}}, got)
+
+	// Without a known type, the key-name heuristic applies.
+	got = config.RedactStruct(nil, config.Requirement{Key: "other", Type: "x.Other"}, raw)
+	require.Equal(t, "***", got.(map[string]any)["key"])
+}
+
+type upstreamCfg struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers" secret:"true"`
+	Peers   map[string]struct {
+		Addr string `yaml:"addr"`
+		Auth string `yaml:"auth" secret:"true"`
+	} `yaml:"peers"`
+}
+
+func TestRedactStruct_MasksTaggedMaps(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	t.Cleanup(config.ResetDiscoveryForTests)
+	config.RegisterRequirement("upstream", upstreamCfg{})
+	reqs := config.Requirements()
+	require.Len(t, reqs, 1)
+
+	raw := map[string]any{
+		"url":     "https://api",
+		"headers": map[string]any{"X-Api": "k1", "Authorization": "Bearer t"},
+		"peers":   map[string]any{"eu": map[string]any{"addr": "eu:443", "auth": "p"}},
+	}
+	got := config.RedactStruct(nil, reqs[0], raw)
+	require.Equal(t, map[string]any{
+		"url":     "https://api",
+		"headers": "***",
+		"peers":   map[string]any{"eu": map[string]any{"addr": "eu:443", "auth": "***"}},
+	}, got)
+}
+
+func TestRedactWithReport_ListsMaskedPaths(t *testing.T) {
+	raw := map[string]any{
+		"db":    map[string]any{"user": "svc", "password": "x"},
+		"hooks": []any{map[string]any{"token": "a"}, map[string]any{"token": "b"}},
+	}
+	got, masked := config.RedactWithReport(nil, "app", raw)
+	require.Equal(t, "***", got.(map[string]any)["db"].(map[string]any)["password"])
+	require.Equal(t, []string{"app.db.password", "app.hooks.token"}, masked)
+
+	_, masked = config.RedactWithReport(nil, "", map[string]any{"user": "svc"})
+	require.Empty(t, masked)
+}
@@ -0,0 +1,94 @@
+package configkit
+
+import (
+	"reflect"
+	"sync"
+
+	uber "go.uber.org/config"
+)
+
+// Registry wraps a provider and memoizes typed, validated config subtrees, so
+// hot paths can re-read config without repeated populate/validate cost.
+//
+// Values returned by Get are shared between callers and must be treated as
+// read-only.
+type Registry struct {
+	mu    sync.RWMutex
+	p     *uber.YAML
+	cache map[registryKey]any
+}
+
+type registryKey struct {
+	key string
+	t   reflect.Type
+}
+
+// NewRegistry returns a Registry reading from p.
+func NewRegistry(p *uber.YAML) *Registry {
+	return &Registry{p: p, cache: map[registryKey]any{}}
+}
+
+// Get returns the subtree at key populated into T and validated, loading it on
+// first use and serving it from the cache afterwards. Errors are not cached.
+func Get[T any](r *Registry, key string) (*T, error) {
+	k := registryKey{key: key, t: reflect.TypeOf((*T)(nil)).Elem()}
+
+	r.mu.RLock()
+	v, ok := r.cache[k]
+	p := r.p
+	r.mu.RUnlock()
+	if ok {
+		return v.(*T), nil
+	}
+
+	cfg, err := populate[T](p, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.p != p {
+		// Replaced while loading; don't cache a value from the old provider.
+		return cfg, nil
+	}
+	if v, ok := r.cache[k]; ok {
+		return v.(*T), nil
+	}
+	r.cache[k] = cfg
+	return cfg, nil
+}
+
+// Invalidate drops cached values for the given keys, or all values if no key
+// is given. The next Get for a dropped key reloads it.
+func (r *Registry) Invalidate(keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(keys) == 0 {
+		clear(r.cache)
+		return
+	}
+	for k := range r.cache {
+		for _, key := range keys {
+			if k.key == key {
+				delete(r.cache, k)
+			}
+		}
+	}
+}
+
+// Replace swaps the underlying provider, e.g. after a config reload, and drops
+// every cached value.
+func (r *Registry) Replace(p *uber.YAML) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.p = p
+	clear(r.cache)
+}
+
+// Provider returns the provider values are currently read from.
+func (r *Registry) Provider() *uber.YAML {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.p
+}
@@ -0,0 +1,53 @@
+package configkit_test
+
+import (
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+type registryHTTP struct {
+	Addr string `yaml:"addr" validate:"required"`
+}
+
+type registryDB struct {
+	Host string `yaml:"host"`
+}
+
+func TestRegistry_GetCachesAndInvalidates(t *testing.T) {
+	r := config.NewRegistry(providerFromYAML(t, "http:\n  addr: \":8080\"\ndb:\n  host: a\n"))
+
+	h1, err := config.Get[registryHTTP](r, "http")
+	require.NoError(t, err)
+	require.Equal(t, ":8080", h1.Addr)
+	h2, err := config.Get[registryHTTP](r, "http")
+	require.NoError(t, err)
+	require.Same(t, h1, h2, "second Get should be served from cache")
+
+	db1, err := config.Get[registryDB](r, "db")
+	require.NoError(t, err)
+
+	r.Invalidate("http")
+	h3, err := config.Get[registryHTTP](r, "http")
+	require.NoError(t, err)
+	require.NotSame(t, h1, h3)
+	db2, err := config.Get[registryDB](r, "db")
+	require.NoError(t, err)
+	require.Same(t, db1, db2, "invalidating http must keep db cached")
+
+	// Reload: new provider, everything re-read.
+	r.Replace(providerFromYAML(t, "http:\n  addr: \":9090\"\ndb:\n  host: b\n"))
+	h4, err := config.Get[registryHTTP](r, "http")
+	require.NoError(t, err)
+	require.Equal(t, ":9090", h4.Addr)
+	db3, err := config.Get[registryDB](r, "db")
+	require.NoError(t, err)
+	require.Equal(t, "b", db3.Host)
+}
+
+func TestRegistry_GetValidates(t *testing.T) {
+	r := config.NewRegistry(providerFromYAML(t, "http: {}\n"))
+	_, err := config.Get[registryHTTP](r, "http")
+	require.ErrorContains(t, err, "validation failed")
+}
@@ -0,0 +1,147 @@
+package configkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	uber "go.uber.org/config"
+	"go.uber.org/fx"
+)
+
+// WithWatch makes Module watch its config files (including standard files
+// that do not exist yet) with fsnotify and rebuild the provider when they
+// change. Module then also provides a *ReloadNotifier delivering each rebuilt
+// provider, and starts the watcher whether or not anything depends on it.
+//
+// A rebuild that fails to load, or fails Check for any registered
+// requirement, is logged and counted by RecordReload; the previous provider
+// stays current. The *uber.YAML in the container is the one loaded at
+// startup; components that should follow edits subscribe to the notifier.
+func WithWatch() ModuleOption {
+	return func(o *moduleOpts) {
+		o.watch = true
+	}
+}
+
+// ReloadNotifier publishes providers rebuilt after config file changes. See
+// WithWatch.
+type ReloadNotifier struct {
+	mu      sync.Mutex
+	current *uber.YAML
+	subs    map[int]func(*uber.YAML)
+	nextID  int
+}
+
+// NewReloadNotifier returns a notifier whose current provider is p. Module
+// constructs it when WithWatch is set; call Publish to drive it manually.
+func NewReloadNotifier(p *uber.YAML) *ReloadNotifier {
+	return &ReloadNotifier{current: p, subs: map[int]func(*uber.YAML){}}
+}
+
+// Current returns the most recently published provider.
+func (n *ReloadNotifier) Current() *uber.YAML {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.current
+}
+
+// Subscribe registers fn to be called with every provider published from now
+// on. Calls happen one at a time, on the watcher's goroutine. The returned
+// func removes the subscription.
+func (n *ReloadNotifier) Subscribe(fn func(*uber.YAML)) (unsubscribe func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := n.nextID
+	n.nextID++
+	n.subs[id] = fn
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subs, id)
+	}
+}
+
+// Publish makes p current and hands it to every subscriber.
+func (n *ReloadNotifier) Publish(p *uber.YAML) {
+	n.mu.Lock()
+	n.current = p
+	subs := make([]func(*uber.YAML), 0, len(n.subs))
+	for _, fn := range n.subs {
+		subs = append(subs, fn)
+	}
+	n.mu.Unlock()
+	for _, fn := range subs {
+		fn(p)
+	}
+}
+
+// newReloadNotifier builds the notifier for Module and runs the file watcher
+// for the app's lifetime.
+func (o moduleOpts) newReloadNotifier(lc fx.Lifecycle, p *uber.YAML) *ReloadNotifier {
+	n := NewReloadNotifier(p)
+	files := watchPaths(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			// Watch before returning, so edits made once the app has
+			// started are never missed.
+			w, err := newFileWatcher(files, WatchOptions{})
+			if err != nil {
+				close(done)
+				return err
+			}
+			go func() {
+				defer close(done)
+				w.run(ctx, func() { o.reload(n) })
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+	return n
+}
+
+// reload rebuilds the provider and publishes it if it is valid.
+func (o moduleOpts) reload(n *ReloadNotifier) {
+	p, err := o.load()
+	if err == nil {
+		err = checkRequirements(p)
+	}
+	RecordReload(err)
+	if err != nil {
+		return
+	}
+	n.Publish(p)
+}
+
+// checkRequirements joins the errors of every failing registered requirement.
+func checkRequirements(p Provider) error {
+	var errs []error
+	for _, r := range Check(p) {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("config: %s (%s): %w", r.Key, r.Type, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watchPaths lists the files p was built from plus the standard config files
+// that may be created later.
+func watchPaths(p *uber.YAML) []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, f := range append(Files(p), candidateFiles("config")...) {
+		if _, ok := seen[f]; !ok {
+			seen[f] = struct{}{}
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package configkit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	uber "go.uber.org/config"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type watchedCfg struct {
+	Greeting string `yaml:"greeting" validate:"required"`
+}
+
+func TestWithWatch_PublishesValidReloads(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	config.ResetReloadsForTests()
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	path := filepath.Join("config", "config.yml")
+	writeFile(t, path, []byte("app:\n  greeting: hello\n"))
+
+	var n *config.ReloadNotifier
+	app := fxtest.New(t,
+		config.Module(config.WithWatch()),
+		fx.Provide(config.ProvideFromKey[watchedCfg]("app")),
+		fx.Invoke(func(*watchedCfg) {}),
+		fx.Populate(&n),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	got := make(chan string, 4)
+	n.Subscribe(func(p *uber.YAML) {
+		var c watchedCfg
+		_ = p.Get("app").Populate(&c)
+		got <- c.Greeting
+	})
+
+	// The watcher is running once the app has started.
+	writeFile(t, path, []byte("app:\n  greeting: hi there\n"))
+	select {
+	case g := <-got:
+		require.Equal(t, "hi there", g)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no reload delivered: %+v", config.Reloads())
+	}
+
+	// A reload that fails validation keeps the previous provider.
+	prev := n.Current()
+	writeFile(t, path, []byte("app:\n  greeting: \"\"\n"))
+	require.Eventually(t, func() bool { return config.Reloads().Errors == 1 }, 3*time.Second, 10*time.Millisecond)
+	require.Same(t, prev, n.Current())
+	require.Empty(t, got)
+}
+
+func TestWithWatch_RunsWithoutSubscribers(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	config.ResetReloadsForTests()
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	path := filepath.Join("config", "config.yml")
+	writeFile(t, path, []byte("app:\n  greeting: hello\n"))
+
+	// Nothing depends on the *ReloadNotifier; the watcher starts anyway.
+	app := fxtest.New(t, config.Module(config.WithWatch()))
+	app.RequireStart()
+	defer app.RequireStop()
+
+	writeFile(t, path, []byte("app:\n  greeting: hi\n"))
+	require.Eventually(t, func() bool { return config.Reloads().Total == 1 }, 3*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,66 @@
+package configkit
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReloadStats counts config reloads in this process. See RecordReload.
+type ReloadStats struct {
+	Total   uint64    // every reload attempt, failed or not
+	Errors  uint64    // attempts that failed
+	Last    time.Time // time of the last attempt; zero before the first
+	LastErr error     // error of the last attempt; nil if it succeeded
+}
+
+var (
+	reloadMu sync.Mutex
+	reloads  ReloadStats
+)
+
+// RecordReload counts one reload attempt and logs it via SetLogger's logger.
+// Code that rebuilds config at runtime (WithWatch, logkit.ReloadLevel, WatchFiles
+// or WatchHTTP callbacks) calls it once per attempt with the outcome so
+// reloads can be observed, e.g. via telemetry.ConfigReloads. Code following a
+// ReloadNotifier must not call it again.
+func RecordReload(err error) {
+	reloadMu.Lock()
+	reloads.Total++
+	reloads.Last = time.Now()
+	reloads.LastErr = err
+	if err != nil {
+		reloads.Errors++
+	}
+	s := reloads
+	reloadMu.Unlock()
+
+	if err != nil {
+		logger.Load().Warn("config: reload failed",
+			zap.Error(err),
+			zap.Uint64("reloads", s.Total),
+			zap.Uint64("reload_errors", s.Errors),
+		)
+		return
+	}
+	logger.Load().Info("config: reloaded",
+		zap.Uint64("reloads", s.Total),
+		zap.Uint64("reload_errors", s.Errors),
+	)
+}
+
+// Reloads returns a snapshot of the reload counters.
+func Reloads() ReloadStats {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return reloads
+}
+
+// ResetReloadsForTests zeroes the reload counters. Exported for tests; do not
+// use in application code.
+func ResetReloadsForTests() {
+	reloadMu.Lock()
+	reloads = ReloadStats{}
+	reloadMu.Unlock()
+}
@@ -0,0 +1,150 @@
+package configkit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HTTPWatchOptions tunes WatchHTTP.
+type HTTPWatchOptions struct {
+	// Client performs the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Interval is the refresh period when the response carries no
+	// Cache-Control max-age, and the retry period after a failed fetch.
+	// Defaults to 30s.
+	Interval time.Duration
+	// MinInterval bounds how often a short max-age can cause re-fetches.
+	// Defaults to 1s.
+	MinInterval time.Duration
+}
+
+// WatchHTTP periodically fetches url and calls onChange with the response
+// body whenever its content changes. The first successful fetch is delivered
+// as well, so callers can layer it (e.g. via WithEmbeddedBytes) and rebuild on
+// every later call.
+//
+// Re-fetches honor the response's Cache-Control max-age and fall back to
+// opts.Interval. Requests carry If-None-Match when the server sent an ETag; a
+// 304 or an identical body (by SHA-256) is not a change. Failed fetches are
+// logged via SetLogger's logger and the last good content stays in effect. It
+// blocks until ctx is done and then returns nil.
+func WatchHTTP(ctx context.Context, url string, opts HTTPWatchOptions, onChange func(body []byte)) error {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = time.Second
+	}
+	if _, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err != nil {
+		return fmt.Errorf("config: watch %q: %w", url, err)
+	}
+
+	var (
+		etag string
+		hash [sha256.Size]byte
+		seen bool
+	)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		res, err := fetch(ctx, opts.Client, url, etag)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			logger.Load().Warn("config: refresh failed; keeping last good config",
+				zap.String("url", url),
+				zap.Error(err),
+			)
+			timer.Reset(opts.Interval)
+			continue
+		}
+
+		if !res.notModified {
+			sum := sha256.Sum256(res.body)
+			if !seen || sum != hash {
+				seen, hash = true, sum
+				onChange(res.body)
+			}
+			etag = res.etag
+		}
+
+		next := opts.Interval
+		if res.maxAge > 0 {
+			next = max(res.maxAge, opts.MinInterval)
+		}
+		timer.Reset(next)
+	}
+}
+
+type fetchResult struct {
+	body        []byte
+	etag        string
+	maxAge      time.Duration
+	notModified bool
+}
+
+func fetch(ctx context.Context, client *http.Client, url, etag string) (fetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	out := fetchResult{maxAge: maxAge(resp.Header.Get("Cache-Control"))}
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		out.notModified = true
+		return out, nil
+	case resp.StatusCode != http.StatusOK:
+		return fetchResult{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return fetchResult{}, err
+	}
+	out.body = buf.Bytes()
+	out.etag = resp.Header.Get("ETag")
+	return out, nil
+}
+
+// maxAge extracts the max-age directive of a Cache-Control header, or 0.
+func maxAge(cc string) time.Duration {
+	for _, d := range strings.Split(cc, ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(d), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		secs, err := strconv.Atoi(strings.Trim(val, `"`))
+		if err != nil || secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
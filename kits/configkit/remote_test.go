@@ -0,0 +1,86 @@
+package configkit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWatchHTTP_ReloadsOnChangeAndKeepsLastGood(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	config.SetLogger(zap.New(core))
+	t.Cleanup(func() { config.SetLogger(nil) })
+
+	var (
+		mu      sync.Mutex
+		body    = "a: 1\n"
+		etag    = `"v1"`
+		failing bool
+		revals  int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			revals++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, max-age=0")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	got := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = config.WatchHTTP(ctx, srv.URL, config.HTTPWatchOptions{
+			Interval:    5 * time.Millisecond,
+			MinInterval: 5 * time.Millisecond,
+		}, func(b []byte) { got <- string(b) })
+	}()
+
+	require.Equal(t, "a: 1\n", <-got)
+
+	// Unchanged content is revalidated via ETag and not re-delivered.
+	require.Eventually(t, func() bool { mu.Lock(); defer mu.Unlock(); return revals >= 2 }, 2*time.Second, 5*time.Millisecond)
+	require.Empty(t, got)
+
+	// Failures are logged; nothing is delivered.
+	mu.Lock()
+	failing = true
+	mu.Unlock()
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("config: refresh failed; keeping last good config").Len() > 0
+	}, 2*time.Second, 5*time.Millisecond)
+	require.Empty(t, got)
+
+	mu.Lock()
+	failing, body, etag = false, "a: 2\n", `"v2"`
+	mu.Unlock()
+	require.Equal(t, "a: 2\n", <-got)
+
+	cancel()
+	<-done
+}
+
+func TestWatchHTTP_InvalidURL(t *testing.T) {
+	err := config.WatchHTTP(context.Background(), "://bad", config.HTTPWatchOptions{}, func([]byte) {})
+	require.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package configkit
+
+import (
+	"fmt"
+
+	"github.com/froppa/stackkit/kits/runtimeinfo"
+	"go.uber.org/zap"
+)
+
+// WithRuntimeInfoCheck checks, when the config is loaded, that a release
+// build carries its metadata (see runtimeinfo.Validate): outside dev, local,
+// and test environments (per Environment), runtimeinfo.Name must be set and
+// runtimeinfo.Version must not be "dev". With strict the problem fails
+// loading, and so startup; otherwise it is logged as a warning to the app's
+// *zap.Logger under Module, or to the logger set by SetLogger otherwise.
+func WithRuntimeInfoCheck(strict bool) ModuleOption {
+	return func(o *moduleOpts) {
+		o.runtimeCheck = true
+		o.runtimeStrict = strict
+	}
+}
+
+// checkRuntimeInfo applies WithRuntimeInfoCheck.
+func (o moduleOpts) checkRuntimeInfo() error {
+	if !o.runtimeCheck {
+		return nil
+	}
+	err := runtimeinfo.Validate(Environment())
+	if err == nil {
+		return nil
+	}
+	if o.runtimeStrict {
+		return fmt.Errorf("config: %w", err)
+	}
+	logger.Load().Warn("config: release build metadata missing", zap.Error(err))
+	return nil
+}
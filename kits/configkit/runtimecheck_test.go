@@ -0,0 +1,46 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/runtimeinfo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithRuntimeInfoCheck(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	config.SetLogger(zap.New(core))
+	t.Cleanup(func() { config.SetLogger(nil) })
+
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(t.TempDir())
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	origName, origVersion := runtimeinfo.Name, runtimeinfo.Version
+	t.Cleanup(func() { runtimeinfo.Name, runtimeinfo.Version = origName, origVersion })
+	runtimeinfo.Name, runtimeinfo.Version = "", "dev"
+
+	t.Setenv("ENV", "dev")
+	_, err := config.Load(config.WithRuntimeInfoCheck(true))
+	require.NoError(t, err)
+
+	t.Setenv("ENV", "prod")
+	_, err = config.Load(config.WithRuntimeInfoCheck(false))
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.FilterMessage("config: release build metadata missing").Len())
+
+	_, err = config.Load(config.WithRuntimeInfoCheck(true))
+	require.ErrorContains(t, err, "runtimeinfo.Name is empty")
+	_, err = config.NewYAML(context.Background(), config.WithRuntimeInfoCheck(true))
+	require.ErrorContains(t, err, "runtimeinfo.Name is empty")
+
+	// Without the option nothing is checked.
+	_, err = config.Load()
+	require.NoError(t, err)
+}
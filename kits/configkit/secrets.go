@@ -0,0 +1,104 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	uber "go.uber.org/config"
+)
+
+// secretSet holds the dotted paths Redact masks regardless of key name.
+type secretSet map[string]struct{}
+
+func (s secretSet) has(path string) bool {
+	_, ok := s[path]
+	return ok
+}
+
+// secretsOf returns the paths marked secret by the layers p was built from:
+// every leaf of a secrets file and every value read through `${file:...}`.
+// Providers not built by configkit have none.
+func secretsOf(p Provider) secretSet {
+	y, ok := p.(*uber.YAML)
+	if !ok {
+		return nil
+	}
+	chain, _ := chainFor(y)
+	var out secretSet
+	for _, l := range chain {
+		for _, path := range l.secrets {
+			if out == nil {
+				out = secretSet{}
+			}
+			out[path] = struct{}{}
+		}
+	}
+	return out
+}
+
+// WithSecretsFile layers the YAML file at path above all regular config files
+// (environment expansion is still applied last). Every key it defines is
+// masked by Redact, whatever its name.
+//
+// The file must exist and, outside Windows, must not be accessible by
+// "other" users (e.g. mode 0600 or 0640).
+func WithSecretsFile(path string) ModuleOption {
+	return func(o *moduleOpts) {
+		o.secrets = append(o.secrets, path)
+	}
+}
+
+// secretLayers validates each secrets file and records the keys it defines.
+func secretLayers(paths []string) ([]layer, error) {
+	out := make([]layer, 0, len(paths))
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: secrets file: %w", err)
+		}
+		if fi.IsDir() {
+			return nil, fmt.Errorf("config: secrets file %q is a directory", path)
+		}
+		if runtime.GOOS != "windows" && fi.Mode().Perm()&0o007 != 0 {
+			return nil, fmt.Errorf("config: secrets file %q is accessible by others (mode %04o)", path, fi.Mode().Perm())
+		}
+
+		src, _ := yamlFileSource(path)
+		p, err := uber.NewYAML(src)
+		if err != nil {
+			return nil, fmt.Errorf("config: secrets file %q: %w", path, err)
+		}
+		var raw any
+		if err := p.Get(uber.Root).Populate(&raw); err != nil {
+			return nil, fmt.Errorf("config: secrets file %q: %w", path, err)
+		}
+		var secrets []string
+		markSecret("", normalize(raw), &secrets)
+
+		out = append(out, layer{name: path, path: path, secret: true, src: src, secrets: secrets})
+	}
+	return out, nil
+}
+
+// markSecret appends the path of every leaf below v to paths. Lists are
+// treated as leaves.
+func markSecret(prefix string, v any, paths *[]string) {
+	if m, ok := v.(map[string]any); ok {
+		for k, val := range m {
+			markSecret(joinKey(prefix, k), val, paths)
+		}
+		return
+	}
+	if prefix == "" {
+		return
+	}
+	*paths = append(*paths, prefix)
+}
+
+func joinKey(prefix, k string) string {
+	if prefix == "" {
+		return k
+	}
+	return prefix + "." + k
+}
@@ -0,0 +1,232 @@
+package configkit
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"weak"
+
+	uber "go.uber.org/config"
+)
+
+// WithStrictTypes rejects scalars whose YAML type does not match the Go field
+// they fill, instead of letting the decoder coerce them: `name: 1.10` into a
+// string field (which silently becomes "1.1"), `debug: "true"` or
+// `port: "8080"` into a bool or int field. Providers built with this option
+// check types before decoding, so ProvideFromKey, PopulateWithDefaults, and
+// Check report every mismatch rather than the first.
+//
+// Ints are accepted for float fields, and fields decoded from text (e.g.
+// time.Duration or types implementing encoding.TextUnmarshaler) accept
+// strings.
+func WithStrictTypes() ModuleOption {
+	return func(o *moduleOpts) {
+		o.strictTypes = true
+	}
+}
+
+// WithStrict rejects keys in the YAML that no field of the target type
+// accepts, such as a misspelled `addrr:`. Providers built with this option make
+// ProvideFromKey and PopulateWithDefaults fail with every unknown path under
+// the key, so the fx app stops at startup; Check reports them either way.
+func WithStrict() ModuleOption {
+	return func(o *moduleOpts) {
+		o.strictKeys = true
+	}
+}
+
+// strictness records which strict checks a provider was built with.
+type strictness uint8
+
+const (
+	strictTypesMode strictness = 1 << iota // WithStrictTypes
+	strictKeysMode                         // WithStrict
+)
+
+// strictness returns the strict checks o enables.
+func (o moduleOpts) strictness() strictness {
+	var s strictness
+	if o.strictTypes {
+		s |= strictTypesMode
+	}
+	if o.strictKeys {
+		s |= strictKeysMode
+	}
+	return s
+}
+
+var (
+	strictMu        sync.Mutex
+	strictProviders = map[weak.Pointer[uber.YAML]]strictness{}
+)
+
+// rememberStrict marks p as built with the strict checks in s.
+func rememberStrict(p *uber.YAML, s strictness) {
+	if s == 0 {
+		return
+	}
+	wp := weak.Make(p)
+	strictMu.Lock()
+	strictProviders[wp] = s
+	strictMu.Unlock()
+	runtime.AddCleanup(p, func(wp weak.Pointer[uber.YAML]) {
+		strictMu.Lock()
+		delete(strictProviders, wp)
+		strictMu.Unlock()
+	}, wp)
+}
+
+// strictnessOf returns the strict checks p was built with.
+func strictnessOf(p Provider) strictness {
+	yp, ok := p.(*uber.YAML)
+	if !ok {
+		return 0
+	}
+	strictMu.Lock()
+	defer strictMu.Unlock()
+	return strictProviders[weak.Make(yp)]
+}
+
+func isStrict(p Provider) bool { return strictnessOf(p)&strictTypesMode != 0 }
+
+// unknownKeysError lists the keys under key that t does not accept, or
+// returns nil. It only checks providers built with WithStrict.
+func unknownKeysError(p Provider, key string, t reflect.Type) error {
+	if strictnessOf(p)&strictKeysMode == 0 {
+		return nil
+	}
+	var raw any
+	if err := p.Get(key).Populate(&raw); err != nil {
+		return nil
+	}
+	unknown := findUnknownKeys(raw, t, "")
+	if len(unknown) == 0 {
+		return nil
+	}
+	full := make([]string, len(unknown))
+	for i, u := range unknown {
+		full[i] = joinKey(key, u)
+	}
+	return fmt.Errorf("config: unknown keys for key %q: %s", key, strings.Join(full, ", "))
+}
+
+// strictTypeIssues returns one "path: expected X, got Y" entry, with paths
+// relative to key, per scalar in the value at key whose YAML type does not
+// match t. It returns nil unless p was built with WithStrictTypes.
+func strictTypeIssues(p Provider, key string, t reflect.Type) []string {
+	if !isStrict(p) {
+		return nil
+	}
+	var raw any
+	if err := listValue(p, key, t).Populate(&raw); err != nil {
+		return nil
+	}
+	var issues []string
+	findTypeMismatches(normalize(raw), t, "", &issues)
+	return issues
+}
+
+// strictTypesError reports the mismatches of key with their full paths.
+func strictTypesError(key string, issues []string) error {
+	full := make([]string, len(issues))
+	for i, issue := range issues {
+		full[i] = joinKey(key, issue)
+	}
+	return fmt.Errorf("config: strict types failed for key %q: %s", key, strings.Join(full, "; "))
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func findTypeMismatches(v any, t reflect.Type, path string, issues *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if v == nil || t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("yaml") == "-" {
+				continue
+			}
+			name, inline := parseYAMLTag(f.Tag.Get("yaml"), f)
+			if inline {
+				findTypeMismatches(m, f.Type, path, issues)
+				continue
+			}
+			if val, ok := m[name]; ok {
+				findTypeMismatches(val, f.Type, joinKey(path, name), issues)
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]any); ok {
+			for k, val := range m {
+				findTypeMismatches(val, t.Elem(), joinKey(path, k), issues)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if s, ok := v.([]any); ok {
+			for i, val := range s {
+				findTypeMismatches(val, t.Elem(), fmt.Sprintf("%s[%d]", path, i), issues)
+			}
+		}
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			*issues = append(*issues, mismatch(path, "string", v))
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			*issues = append(*issues, mismatch(path, "bool", v))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !isYAMLInt(v) {
+			*issues = append(*issues, mismatch(path, "int", v))
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(float64); !ok && !isYAMLInt(v) {
+			*issues = append(*issues, mismatch(path, "float", v))
+		}
+	}
+}
+
+func isYAMLInt(v any) bool {
+	switch v.(type) {
+	case int, int64, uint64:
+		return true
+	}
+	return false
+}
+
+func mismatch(path, want string, v any) string {
+	got := "map"
+	switch v.(type) {
+	case string:
+		got = "string"
+	case bool:
+		got = "bool"
+	case int, int64, uint64:
+		got = "int"
+	case float64:
+		got = "float"
+	case []any:
+		got = "list"
+	}
+	if got == "map" || got == "list" {
+		return fmt.Sprintf("%s: expected %s, got %s", path, want, got)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s %#v", path, want, got, v)
+}
@@ -0,0 +1,55 @@
+package configkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+type strictConfig struct {
+	Name    string        `yaml:"name"`
+	Port    int           `yaml:"port"`
+	Debug   bool          `yaml:"debug"`
+	Rate    float64       `yaml:"rate"`
+	Timeout time.Duration `yaml:"timeout"`
+	Tags    []string      `yaml:"tags"`
+}
+
+func TestWithStrictTypes(t *testing.T) {
+	good := "app:\n  name: api\n  port: 8080\n  debug: true\n  rate: 1\n  timeout: 5s\n  tags: [a, b]\n"
+	bad := "app:\n  name: 1.10\n  port: 8080\n  debug: \"true\"\n  rate: 0.5\n  timeout: 5s\n  tags: [a, 2]\n"
+
+	load := func(src string, opts ...config.ModuleOption) config.Provider {
+		t.Helper()
+		opts = append(opts, config.WithEmbeddedBytes([]byte(src)))
+		p, err := config.NewYAML(context.Background(), opts...)
+		require.NoError(t, err)
+		return p
+	}
+
+	_, err := config.PopulateWithDefaults(load(good, config.WithStrictTypes()), "app", strictConfig{})
+	require.NoError(t, err)
+
+	_, err = config.PopulateWithDefaults(load(bad, config.WithStrictTypes()), "app", strictConfig{})
+	require.ErrorContains(t, err, "app.name: expected string, got float 1.1")
+	require.ErrorContains(t, err, `app.debug: expected bool, got string "true"`)
+	require.ErrorContains(t, err, "app.tags[1]: expected string, got int 2")
+
+	// Without the option the unquoted version is silently coerced.
+	cfg, err := config.PopulateWithDefaults(load("app:\n  name: 1.10\n"), "app", strictConfig{})
+	require.NoError(t, err)
+	require.Equal(t, "1.1", cfg.Name)
+}
+
+func TestWithStrict_ListsUnknownKeys(t *testing.T) {
+	config.ResetDiscoveryForTests()
+	src := []byte("app:\n  name: api\n  addrr: :80\n  debg: true\n")
+	p, err := config.NewYAML(context.Background(), config.WithStrict(), config.WithEmbeddedBytes(src))
+	require.NoError(t, err)
+
+	_, err = config.ProvideFromKey[strictConfig]("app")(p)
+	require.EqualError(t, err, `config: unknown keys for key "app": app.addrr, app.debg`)
+}
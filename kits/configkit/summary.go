@@ -0,0 +1,48 @@
+package configkit
+
+import (
+	"strings"
+)
+
+// maxSummaryValue bounds the length of a value returned by Summary.
+const maxSummaryValue = 128
+
+// Summary reads each of keys (dotted paths) from p and returns its value
+// rendered as a string, for attaching a small, safe view of the effective
+// config to telemetry or debug output.
+//
+// Only the listed keys are read. Unset keys and maps or lists are skipped,
+// secret-looking keys (and values from WithSecretsFile) are replaced with
+// "***", and long values are truncated to 128 bytes.
+func Summary(p Provider, keys []string) map[string]string {
+	out := make(map[string]string, len(keys))
+	secrets := secretsOf(p)
+	for _, key := range keys {
+		v := p.Get(key)
+		if !v.HasValue() {
+			continue
+		}
+		var raw any
+		if err := v.Populate(&raw); err != nil {
+			continue
+		}
+		switch raw.(type) {
+		case nil, map[any]any, map[string]any, []any:
+			continue
+		}
+		last := key
+		if i := strings.LastIndexByte(key, '.'); i >= 0 {
+			last = key[i+1:]
+		}
+		if isSecretKey(last) || secrets.has(key) {
+			out[key] = "***"
+			continue
+		}
+		s := asString(raw)
+		if len(s) > maxSummaryValue {
+			s = s[:maxSummaryValue]
+		}
+		out[key] = s
+	}
+	return out
+}
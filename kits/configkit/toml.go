@@ -0,0 +1,50 @@
+package configkit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	uber "go.uber.org/config"
+)
+
+// WithTOMLBytes adds a TOML document as a low-precedence source, like
+// WithEmbeddedBytes. It is converted to YAML before layering, so `${VAR}`
+// placeholders in string values are expanded and YAML files override it.
+func WithTOMLBytes(b []byte) ModuleOption {
+	return func(o *moduleOpts) {
+		o.extra = append(o.extra, layer{name: "toml", src: tomlSource(b)})
+	}
+}
+
+// isTOML reports whether path names a TOML file.
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// tomlFileLayer returns a layer reading the TOML file at path.
+func tomlFileLayer(path string) layer {
+	b, err := os.ReadFile(path)
+	src := tomlSource(b)
+	if err != nil {
+		src = uber.Source(errReader{err})
+	}
+	return layer{name: path, path: path, src: src}
+}
+
+// tomlSource decodes b as TOML and returns it as a YAML source. A decode
+// error is reported when the provider is built.
+func tomlSource(b []byte) uber.YAMLOption {
+	var doc map[string]any
+	if err := toml.Unmarshal(b, &doc); err != nil {
+		return uber.Source(errReader{fmt.Errorf("toml: %w", err)})
+	}
+	return uber.Static(doc)
+}
+
+// errReader fails every read, turning an error into a failing uber.Source.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,62 @@
+package configkit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type tomlServer struct {
+	Addr    string        `yaml:"addr" validate:"required"`
+	Timeout time.Duration `yaml:"timeout"`
+	Tags    []string      `yaml:"tags"`
+	Region  string        `yaml:"region"`
+}
+
+func TestWithTOMLBytes_PopulatesYAMLTags(t *testing.T) {
+	t.Setenv("TOML_REGION", "eu-west-1")
+	doc := []byte(`
+[server]
+addr = ":8080"
+timeout = "5s"
+tags = ["a", "b"]
+region = "${TOML_REGION:us-east-1}"
+`)
+	var got *tomlServer
+	fxtest.New(t,
+		config.Module(config.WithTOMLBytes(doc)),
+		fx.Provide(config.ProvideFromKey[tomlServer]("server")),
+		fx.Populate(&got),
+	).RequireStart().RequireStop()
+
+	require.Equal(t, &tomlServer{Addr: ":8080", Timeout: 5 * time.Second, Tags: []string{"a", "b"}, Region: "eu-west-1"}, got)
+}
+
+func TestTOMLConfigFile_OverriddenByYAML(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.toml"), []byte("[server]\naddr = \":8080\"\nregion = \"toml\"\n"))
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("server:\n  region: yaml\n"))
+
+	p, err := config.Load()
+	require.NoError(t, err)
+	var got tomlServer
+	require.NoError(t, p.Get("server").Populate(&got))
+	require.Equal(t, ":8080", got.Addr)
+	require.Equal(t, "yaml", got.Region)
+	require.Contains(t, config.Files(p), filepath.Join("config", "config.toml"))
+}
+
+func TestWithTOMLBytes_InvalidTOML(t *testing.T) {
+	_, err := config.Load(config.WithTOMLBytes([]byte("[server\n")))
+	require.ErrorContains(t, err, `source "toml"`)
+}
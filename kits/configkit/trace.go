@@ -0,0 +1,133 @@
+package configkit
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"weak"
+
+	uber "go.uber.org/config"
+)
+
+// chains remembers the source chain each provider was built from, so values
+// can be traced back to the source that set them. Entries are dropped once
+// the provider is garbage collected.
+var (
+	chainMu sync.Mutex
+	chains  = map[weak.Pointer[uber.YAML]][]layer{}
+)
+
+func rememberChain(p *uber.YAML, chain []layer) {
+	wp := weak.Make(p)
+	chainMu.Lock()
+	chains[wp] = chain
+	chainMu.Unlock()
+	runtime.AddCleanup(p, func(wp weak.Pointer[uber.YAML]) {
+		chainMu.Lock()
+		delete(chains, wp)
+		chainMu.Unlock()
+	}, wp)
+}
+
+// LoadedSources returns the sources p was built from by Module, NewYAML, or
+// the CLI loaders, lowest precedence first. Files and mounted directories
+// appear as paths; other sources as markers such as "embedded", "toml",
+// "source[0]" (WithSources), and "env" (always last). Providers not built by
+// configkit yield nil.
+func LoadedSources(p *uber.YAML) []string {
+	chain, _ := chainFor(p)
+	if len(chain) == 0 {
+		return nil
+	}
+	names := make([]string, len(chain))
+	for i, l := range chain {
+		names[i] = l.name
+	}
+	return names
+}
+
+func chainFor(p *uber.YAML) ([]layer, bool) {
+	chainMu.Lock()
+	defer chainMu.Unlock()
+	chain, ok := chains[weak.Make(p)]
+	return chain, ok
+}
+
+// TraceStep is the value of a key after a single source was layered on top
+// of all lower-precedence sources.
+type TraceStep struct {
+	Source  string // file path or marker ("embedded", "env", "source[N]")
+	Found   bool   // key present after this source was applied
+	Value   any    // normalized value after this source was applied
+	Changed bool   // value differs from the previous step
+}
+
+// TraceResult reports where the final value of a key came from.
+type TraceResult struct {
+	Key    string
+	Found  bool
+	Value  any    // final value
+	Source string // last source that changed the value; "" if never set
+	Steps  []TraceStep
+}
+
+// Trace reports, for a dotted key such as "http.addr", the final value and
+// which source in the precedence chain set it. It rebuilds the provider one
+// source at a time and records where the value changes.
+//
+// Only providers built by Module, NewYAML, or other configkit loaders can be
+// traced, since the source chain is not recoverable from a bare *uber.YAML.
+func Trace(p *uber.YAML, key string) (TraceResult, error) {
+	res := TraceResult{Key: key}
+	chain, ok := chainFor(p)
+	if !ok {
+		return res, fmt.Errorf("config: provider was not built by configkit; cannot trace %q", key)
+	}
+
+	var (
+		prev      any
+		prevFound bool
+	)
+	for i := range chain {
+		// Earlier prefixes are built without env expansion; placeholders are
+		// reported verbatim until the env layer resolves them.
+		opts := make([]uber.YAMLOption, 0, i+1)
+		for _, l := range chain[:i+1] {
+			opts = append(opts, l.src)
+		}
+		step, err := uber.NewYAML(opts...)
+		if err != nil {
+			return res, fmt.Errorf("config: trace %q at source %q: %w", key, chain[i].name, err)
+		}
+		v, found, err := lookup(step, key)
+		if err != nil {
+			return res, fmt.Errorf("config: trace %q at source %q: %w", key, chain[i].name, err)
+		}
+		changed := found != prevFound || !reflect.DeepEqual(v, prev)
+		res.Steps = append(res.Steps, TraceStep{Source: chain[i].name, Found: found, Value: v, Changed: changed})
+		if changed {
+			res.Source = chain[i].name
+		}
+		prev, prevFound = v, found
+	}
+
+	res.Value, res.Found = prev, prevFound
+	if !res.Found {
+		res.Source = ""
+	}
+	return res, nil
+}
+
+// lookup returns the normalized value stored at key, if any.
+func lookup(p *uber.YAML, key string) (any, bool, error) {
+	v := p.Get(key)
+	if !v.HasValue() {
+		return nil, false, nil
+	}
+	var raw any
+	if err := v.Populate(&raw); err != nil {
+		return nil, false, err
+	}
+	return normalize(raw), true, nil
+}
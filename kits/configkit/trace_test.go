@@ -0,0 +1,91 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace_ReportsWinningSource(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("http:\n  addr: \":8080\"\n  read_timeout_ms: 5\n"))
+	cliFile := filepath.Join(tmp, "cli.yml")
+	writeFile(t, cliFile, []byte("http:\n  addr: \":9090\"\n"))
+
+	p, err := config.NewYAML(context.Background(), config.WithFile(cliFile))
+	require.NoError(t, err)
+
+	res, err := config.Trace(p, "http.addr")
+	require.NoError(t, err)
+	require.True(t, res.Found)
+	require.Equal(t, ":9090", res.Value)
+	require.Equal(t, cliFile, res.Source)
+	require.Len(t, res.Steps, 3)
+	require.Equal(t, filepath.Join("config", "config.yml"), res.Steps[0].Source)
+	require.Equal(t, ":8080", res.Steps[0].Value)
+	require.False(t, res.Steps[2].Changed, "env layer should not change the value")
+
+	res, err = config.Trace(p, "http.read_timeout_ms")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("config", "config.yml"), res.Source)
+}
+
+func TestTrace_EnvExpansion(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("http:\n  addr: ${TRACE_ADDR:\":8080\"}\n"))
+	t.Setenv("TRACE_ADDR", ":7070")
+
+	p, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+
+	res, err := config.Trace(p, "http.addr")
+	require.NoError(t, err)
+	require.Equal(t, ":7070", res.Value)
+	require.Equal(t, "env", res.Source)
+}
+
+func TestTrace_MissingKeyAndForeignProvider(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	p, err := config.NewYAML(context.Background())
+	require.NoError(t, err)
+	res, err := config.Trace(p, "nope")
+	require.NoError(t, err)
+	require.False(t, res.Found)
+	require.Empty(t, res.Source)
+
+	foreign := providerFromYAML(t, "a: 1\n")
+	_, err = config.Trace(foreign, "a")
+	require.Error(t, err)
+}
+
+func TestLoadedSources_InPrecedenceOrder(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("a: 1\n"))
+	p, err := config.NewYAML(context.Background(), config.WithEmbeddedBytes([]byte("a: 0\n")))
+	require.NoError(t, err)
+
+	// A later load does not change what p reports.
+	_, err = config.NewYAML(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join("config", "config.yml"), "embedded", "env"}, config.LoadedSources(p))
+}
@@ -0,0 +1,26 @@
+package configkit
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidation adds a custom rule to the validator shared by
+// ProvideFromKey, PopulateWithDefaults, and Check, so config types can use
+// e.g. `validate:"hostport"`. The validator is not safe for registration
+// while validating, so call it from a module's init() (or otherwise before
+// the fx app starts).
+func RegisterValidation(tag string, fn validator.Func) error {
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("config: register validation %q: %w", tag, err)
+	}
+	return nil
+}
+
+// RegisterAlias makes alias expand to tags, e.g.
+// RegisterAlias("port", "gte=1,lte=65535"). Like RegisterValidation, it must
+// run before the fx app starts.
+func RegisterAlias(alias, tags string) {
+	validate.RegisterAlias(alias, tags)
+}
@@ -0,0 +1,39 @@
+package configkit_test
+
+import (
+	"net"
+	"testing"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	if err := config.RegisterValidation("test_hostport", func(fl validator.FieldLevel) bool {
+		_, _, err := net.SplitHostPort(fl.Field().String())
+		return err == nil
+	}); err != nil {
+		panic(err)
+	}
+	config.RegisterAlias("test_port", "gte=1,lte=65535")
+}
+
+type customRuleCfg struct {
+	Upstream string `yaml:"upstream" validate:"test_hostport"`
+	Port     int    `yaml:"port" validate:"test_port"`
+}
+
+func TestRegisterValidation_AppliesToProvideFromKey(t *testing.T) {
+	config.ResetDiscoveryForTests()
+
+	cfg, err := config.ProvideFromKey[customRuleCfg]("proxy")(providerFromYAML(t, "proxy:\n  upstream: db:5432\n  port: 80\n"))
+	require.NoError(t, err)
+	require.Equal(t, "db:5432", cfg.Upstream)
+
+	_, err = config.ProvideFromKey[customRuleCfg]("proxy")(providerFromYAML(t, "proxy:\n  upstream: db\n  port: 0\n"))
+	require.ErrorContains(t, err, "proxy.upstream: test_hostport")
+	require.ErrorContains(t, err, "proxy.port: test_port")
+
+	require.Error(t, config.RegisterValidation("", nil))
+}
@@ -0,0 +1,158 @@
+package configkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	uber "go.uber.org/config"
+)
+
+// Files returns the paths of the config files p was built from, lowest
+// precedence first. Only providers built by configkit are known; others yield
+// nil.
+func Files(p *uber.YAML) []string {
+	chain, _ := chainFor(p)
+	var out []string
+	for _, l := range chain {
+		if l.path != "" {
+			out = append(out, l.path)
+		}
+	}
+	return out
+}
+
+// WatchOptions tunes WatchFiles.
+type WatchOptions struct {
+	// Interval is how often files are polled when their directory cannot be
+	// watched, e.g. because it does not exist yet. Defaults to 250ms.
+	Interval time.Duration
+	// Debounce is how long files must stay unchanged before onChange fires,
+	// so editors that save in several writes trigger a single callback.
+	// Defaults to 200ms.
+	Debounce time.Duration
+}
+
+// WatchFiles watches paths with fsnotify and calls onChange once they have
+// changed and then settled for opts.Debounce. Files that are created, removed,
+// or rewritten, including through a swapped symlink as in Kubernetes
+// ConfigMaps, all count as changes. It blocks until ctx is done and then
+// returns nil.
+func WatchFiles(ctx context.Context, paths []string, opts WatchOptions, onChange func()) error {
+	w, err := newFileWatcher(paths, opts)
+	if err != nil {
+		return err
+	}
+	w.run(ctx, onChange)
+	return nil
+}
+
+// fileWatcher watches the directories holding a set of files, polling the
+// files whose directory it could not watch.
+type fileWatcher struct {
+	paths   []string
+	names   map[string]struct{}
+	opts    WatchOptions
+	fs      *fsnotify.Watcher
+	polling bool
+}
+
+// newFileWatcher starts watching paths; changes from then on are reported by
+// run.
+func newFileWatcher(paths []string, opts WatchOptions) (*fileWatcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 250 * time.Millisecond
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = 200 * time.Millisecond
+	}
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("config: watch: %w", err)
+	}
+	w := &fileWatcher{paths: paths, names: map[string]struct{}{}, opts: opts, fs: fs}
+	dirs := map[string]struct{}{}
+	for _, p := range paths {
+		w.names[filepath.Clean(p)] = struct{}{}
+		dir := filepath.Dir(p)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		dirs[dir] = struct{}{}
+		if err := fs.Add(dir); err != nil {
+			w.polling = true
+		}
+	}
+	return w, nil
+}
+
+// run reports changes to onChange until ctx is done, then stops watching.
+func (w *fileWatcher) run(ctx context.Context, onChange func()) {
+	defer func() { _ = w.fs.Close() }()
+
+	var poll <-chan time.Time
+	if w.polling {
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	settle := time.NewTimer(w.opts.Debounce)
+	settle.Stop()
+	defer settle.Stop()
+
+	last := snapshot(w.paths)
+	changed := func(named bool) {
+		cur := snapshot(w.paths)
+		if named || !sameSnapshot(cur, last) {
+			last = cur
+			settle.Reset(w.opts.Debounce)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			// Other names in the directory matter when they are symlink
+			// targets, so compare stamps for those.
+			_, named := w.names[filepath.Clean(ev.Name)]
+			changed(named)
+		case <-w.fs.Errors:
+		case <-poll:
+			changed(false)
+		case <-settle.C:
+			onChange()
+		}
+	}
+}
+
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func snapshot(paths []string) []fileStamp {
+	out := make([]fileStamp, len(paths))
+	for i, p := range paths {
+		if fi, err := os.Stat(p); err == nil {
+			out[i] = fileStamp{exists: true, size: fi.Size(), modTime: fi.ModTime()}
+		}
+	}
+	return out
+}
+
+func sameSnapshot(a, b []fileStamp) bool {
+	for i := range a {
+		if a[i].exists != b[i].exists || a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
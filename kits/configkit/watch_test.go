@@ -0,0 +1,59 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	config "github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles_ListsResolvedFiles(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	_ = os.Chdir(tmp)
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	writeFile(t, filepath.Join("config", "config.yml"), []byte("a: 1\n"))
+	cli := filepath.Join(tmp, "cli.yml")
+	writeFile(t, cli, []byte("a: 2\n"))
+
+	p, err := config.NewYAML(context.Background(), config.WithFile(cli))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join("config", "config.yml"), cli}, config.Files(p))
+}
+
+func TestWatchFiles_DebouncesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeFile(t, path, []byte("a: 1\n"))
+
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = config.WatchFiles(ctx, []string{path}, config.WatchOptions{
+			Interval: 10 * time.Millisecond,
+			Debounce: 100 * time.Millisecond,
+		}, func() { calls.Add(1) })
+	}()
+
+	// Several quick saves should collapse into one callback.
+	time.Sleep(30 * time.Millisecond)
+	for i := range 3 {
+		writeFile(t, path, []byte("a: "+string(rune('2'+i))+"\nb: true\n"))
+		time.Sleep(20 * time.Millisecond)
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Removal counts as a change as well.
+	require.NoError(t, os.Remove(path))
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -48,6 +48,14 @@ type Options struct {
 	ShowSupplied bool
 	// Emit a compact startup/shutdown summary with counters and durations.
 	Summaries bool
+
+	// OnStarted, if set, is called once the app has finished starting with the
+	// result of the start (nil on success). Useful for flipping readiness or
+	// alerting without parsing logs.
+	OnStarted func(err error)
+	// OnStopped, if set, is called once the app has finished stopping with the
+	// result of the stop (nil on success).
+	OnStopped func(err error)
 }
 
 // DefaultOptions keeps boot logs tidy but informative.
@@ -151,6 +159,9 @@ func (m *MinimalZap) LogEvent(e fxevent.Event) {
 			m.log("fx.onstop_ok", zap.String("callee", ev.FunctionName), zap.String("runtime", ev.Runtime.String()))
 		}
 	case *fxevent.Started:
+		if m.O.OnStarted != nil {
+			defer m.O.OnStarted(ev.Err)
+		}
 		if ev.Err != nil {
 			m.logErr("fx.start_error", zap.Error(ev.Err))
 		} else {
@@ -170,6 +181,9 @@ func (m *MinimalZap) LogEvent(e fxevent.Event) {
 	case *fxevent.Stopping:
 		m.log("fx.stopping", zap.String("signal", strings.ToUpper(ev.Signal.String())))
 	case *fxevent.Stopped:
+		if m.O.OnStopped != nil {
+			defer m.O.OnStopped(ev.Err)
+		}
 		if ev.Err != nil {
 			m.logErr("fx.stop_error", zap.Error(ev.Err))
 		} else {
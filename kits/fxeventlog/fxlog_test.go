@@ -0,0 +1,56 @@
+package fxeventlog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/fxeventlog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMinimalZap_LifecycleCallbacks(t *testing.T) {
+	var started, stopped []error
+	opts := fxeventlog.DefaultOptions
+	opts.OnStarted = func(err error) { started = append(started, err) }
+	opts.OnStopped = func(err error) { stopped = append(stopped, err) }
+
+	app := fx.New(
+		fx.WithLogger(func() fxevent.Logger {
+			return fxeventlog.NewWithOptions(zap.NewNop(), opts)
+		}),
+	)
+	require.NoError(t, app.Start(context.Background()))
+	require.Equal(t, []error{nil}, started)
+	require.Empty(t, stopped)
+
+	require.NoError(t, app.Stop(context.Background()))
+	require.Equal(t, []error{nil}, stopped)
+}
+
+func TestMinimalZap_OnStartedReceivesError(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	var got error
+	opts := fxeventlog.DefaultOptions
+	opts.OnStarted = func(err error) { got = err }
+
+	boom := errors.New("boom")
+	m := fxeventlog.NewWithOptions(zap.New(core), opts)
+	m.LogEvent(&fxevent.Started{Err: boom})
+
+	require.ErrorIs(t, got, boom)
+	require.Equal(t, 1, logs.FilterMessage("fx.start_error").Len())
+}
+
+func TestMinimalZap_NilCallbacks(t *testing.T) {
+	m := fxeventlog.NewMinimal(zap.NewNop())
+	require.NotPanics(t, func() {
+		m.LogEvent(&fxevent.Started{})
+		m.LogEvent(&fxevent.Stopped{})
+	})
+}
@@ -0,0 +1,192 @@
+package healthkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Check probes one dependency (database, broker, ...) for readiness. Modules
+// contribute checks to `group:"readiness.checks"` (or its alias
+// `group:"health.checks"`); the health service runs every probe each
+// check_interval in the background and reports unready while any check is
+// failing. Health requests only read the latest results, so probe traffic
+// does not grow with request volume.
+//
+// Like Kubernetes probes, a check only changes state after enough
+// consecutive results: FailureThreshold failures to start failing and
+// SuccessThreshold successes to recover. Both default to 1, so a single
+// result flips the state. The first result always sets the state directly.
+//
+//	func NewDBCheck(db *sql.DB) *healthkit.Check {
+//	    return &healthkit.Check{Name: "db", Probe: db.PingContext, FailureThreshold: 3}
+//	}
+type Check struct {
+	// Name is reported by the health endpoint while the check is failing.
+	// Required.
+	Name string
+	// Probe returns nil when the dependency is healthy. Its context is
+	// cancelled after Timeout. Required.
+	Probe func(ctx context.Context) error
+	// Timeout bounds each probe. Defaults to check_interval.
+	Timeout time.Duration
+	// FailureThreshold is the number of consecutive failures before a
+	// passing check counts as failing. Values below 1 mean 1.
+	FailureThreshold int
+	// SuccessThreshold is the number of consecutive successes before a
+	// failing check counts as passing again. Values below 1 mean 1.
+	SuccessThreshold int
+
+	probing   atomic.Bool // a probe is in flight
+	mu        sync.Mutex
+	observed  bool
+	passing   bool
+	fails     int
+	successes int
+	lastErr   error
+}
+
+// checkStatus is the health endpoint's view of one check.
+type checkStatus struct {
+	Name    string `json:"name"`
+	Passing bool   `json:"passing"`
+	Error   string `json:"error,omitempty"` // latest probe error
+}
+
+func (c *Check) status() checkStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := checkStatus{Name: c.Name, Passing: c.passing}
+	if c.lastErr != nil {
+		st.Error = c.lastErr.Error()
+	}
+	return st
+}
+
+// Passing reports whether the check currently counts as healthy. It is false
+// until the first probe has completed.
+func (c *Check) Passing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.passing
+}
+
+// record applies one probe result and reports whether the state changed.
+func (c *Check) record(err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = err
+	was := c.passing
+	if !c.observed {
+		c.observed, c.passing = true, err == nil
+		return c.passing != was
+	}
+	if err != nil {
+		c.successes = 0
+		c.fails++
+		if c.passing && c.fails >= max(c.FailureThreshold, 1) {
+			c.passing = false
+		}
+	} else {
+		c.fails = 0
+		c.successes++
+		if !c.passing && c.successes >= max(c.SuccessThreshold, 1) {
+			c.passing = true
+		}
+	}
+	return c.passing != was
+}
+
+// validateChecks rejects checks the health service could not run.
+func validateChecks(checks []*Check) error {
+	for i, c := range checks {
+		switch {
+		case c == nil:
+			return fmt.Errorf("healthkit: readiness check %d is nil", i)
+		case c.Name == "":
+			return fmt.Errorf("healthkit: readiness check %d has no name", i)
+		case c.Probe == nil:
+			return fmt.Errorf("healthkit: readiness check %q has no probe", c.Name)
+		}
+	}
+	return nil
+}
+
+// runChecks probes every check immediately and then every interval until ctx
+// is done. Checks are probed concurrently, so a slow probe delays only its
+// own check.
+func (h *Health) runChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, c := range h.checks {
+			// A probe still running from an earlier round is not started again.
+			if c.probing.CompareAndSwap(false, true) {
+				go h.probe(ctx, c, interval)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe runs c's probe once and records the result. A probe that outlives its
+// timeout, ignoring its context, is recorded as failed on time and stays in
+// flight until it returns.
+func (h *Health) probe(ctx context.Context, c *Check, interval time.Duration) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = interval
+	}
+	pctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer c.probing.Store(false)
+		done <- c.Probe(pctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-pctx.Done():
+		err = pctx.Err()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if !c.record(err) {
+		return
+	}
+	if err != nil {
+		h.log.Warn("readiness check failing", zap.String("check", c.Name), zap.Error(err))
+	} else {
+		h.log.Info("readiness check passing", zap.String("check", c.Name))
+	}
+}
+
+// checkStatuses returns the state of every check, in registration order.
+func (h *Health) checkStatuses() []checkStatus {
+	out := make([]checkStatus, 0, len(h.checks))
+	for _, c := range h.checks {
+		out = append(out, c.status())
+	}
+	return out
+}
+
+// failingChecks returns the names of checks that are not passing.
+func (h *Health) failingChecks() []string {
+	var out []string
+	for _, c := range h.checks {
+		if !c.Passing() {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
@@ -2,7 +2,7 @@
 // applications. It is designed for use with container orchestrators and load
 // balancers like Kubernetes.
 //
-// Two integration modes are supported:
+// Three integration modes are supported:
 //
 //  1. Dedicated server (Module): starts its own HTTP server on a configurable
 //     port. This is the recommended approach as it isolates health checks
@@ -10,16 +10,23 @@
 //  2. Mux attachment (MuxModule): attaches a /health handler to an existing
 //     *http.ServeMux provided by the application. Useful if you already run
 //     an HTTP server and want to avoid a second port.
+//  3. Handler group (HandlerModule): contributes /health to httpkit's
+//     `group:"http.handlers"`, so it is served by httpkit's server without
+//     the application touching the mux.
 package healthkit
 
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"net/http"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/httpkit"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -45,6 +52,23 @@ func MuxModule() fx.Option {
 	)
 }
 
+// HandlerModule provides health reporting through httpkit's handler group.
+// It includes the core Health service and contributes a /health handler to
+// `group:"http.handlers"`, Health.Ready as httpkit's readiness func for its
+// `readiness_gate` option, the probe paths for the gate to exempt, and a drain
+// func so httpkit's server keeps serving through the pre-stop delay.
+func HandlerModule() fx.Option {
+	return fx.Module("health/handler",
+		fx.Provide(configkit.ProvideFromKey[Config]("health")),
+		fx.Provide(New),
+		fx.Provide(fx.Annotate(NewHandler, fx.ResultTags(`group:"http.handlers"`))),
+		fx.Provide(fx.Annotate(newProbeHandlers, fx.ResultTags(`group:"http.handlers,flatten"`))),
+		fx.Provide(fx.Annotate(readyFunc, fx.ResultTags(`name:"`+httpkit.ReadyFuncName+`"`))),
+		fx.Provide(fx.Annotate(drainFunc, fx.ResultTags(`name:"`+httpkit.DrainFuncName+`"`))),
+		fx.Provide(fx.Annotate(exemptPaths, fx.ResultTags(`group:"`+httpkit.ReadinessExemptGroup+`,flatten"`))),
+	)
+}
+
 // Config defines configuration for the Health service.
 type Config struct {
 	// Port is the network address for the dedicated health server.
@@ -52,17 +76,39 @@ type Config struct {
 	// Only used by ServerModule(), ignored by MuxModule().
 	Port string `yaml:"port"`
 
+	// Path is where the health endpoint is served. Defaults to "/health".
+	Path string `yaml:"path" validate:"omitempty,startswith=/"`
+
 	// StartupDelay is the duration to wait after the application has started
 	// before reporting readiness. Defaults to 200ms if not set.
 	StartupDelay time.Duration `yaml:"startup_delay"`
+
+	// CheckInterval is how often readiness checks (see Check) are probed, and
+	// the default timeout of each probe. Defaults to 10s if not set.
+	CheckInterval time.Duration `yaml:"check_interval" validate:"gte=0"`
+
+	// SeparateProbes also serves /livez, reporting liveness only, and
+	// /readyz, reporting readiness like /health, for orchestrators that
+	// probe them separately.
+	SeparateProbes bool `yaml:"separate_probes"`
+
+	// PreStopDelay is how long the service keeps serving, while reporting
+	// unready, after stop begins and before it reports not live, so load
+	// balancers stop routing new traffic first. Defaults to 0.
+	PreStopDelay time.Duration `yaml:"pre_stop_delay" validate:"gte=0"`
 }
 
 // Health tracks and reports liveness and readiness state.
 type Health struct {
-	ready atomic.Bool
-	live  atomic.Bool
-	cfg   *Config
-	log   *zap.Logger
+	ready  atomic.Bool
+	live   atomic.Bool
+	gated  atomic.Bool // readiness waits for OnStarted instead of the timer
+	gates  []*Gate
+	checks []*Check
+	cfg    *Config
+	log    *zap.Logger
+
+	drainOnce sync.Once
 }
 
 // Params defines the dependencies required to construct the Health service.
@@ -73,32 +119,59 @@ type Params struct {
 	Logger *zap.Logger
 	// The Config is now marked as optional, as it may not be present in the YAML.
 	Config *Config `optional:"true"`
+	// Gates hold readiness open until each one is released.
+	Gates []*Gate `group:"readiness.gates"`
+	// Checks are probed periodically; readiness requires all to pass.
+	Checks []*Check `group:"readiness.checks"`
+	// HealthChecks is an alias group for Checks.
+	HealthChecks []*Check `group:"health.checks"`
 }
 
 // New constructs a new Health service and attaches hooks to manage its state
-// according to the application's lifecycle.
-func New(p Params) *Health {
+// according to the application's lifecycle. It fails when a readiness check
+// lacks a Name or a Probe.
+func New(p Params) (*Health, error) {
+	checks := append(p.Checks, p.HealthChecks...)
+	if err := validateChecks(checks); err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		Port:         ":8081",
-		StartupDelay: 200 * time.Millisecond,
+		Port:          ":8081",
+		Path:          "/health",
+		StartupDelay:  200 * time.Millisecond,
+		CheckInterval: 10 * time.Second,
 	}
 	if p.Config != nil {
 		cfg = &Config{
-			Port:         p.Config.Port,
-			StartupDelay: p.Config.StartupDelay,
+			Port:           p.Config.Port,
+			Path:           p.Config.Path,
+			StartupDelay:   p.Config.StartupDelay,
+			CheckInterval:  p.Config.CheckInterval,
+			SeparateProbes: p.Config.SeparateProbes,
+			PreStopDelay:   p.Config.PreStopDelay,
 		}
 		if cfg.Port == "" {
 			cfg.Port = ":8081"
 		}
+		if cfg.Path == "" {
+			cfg.Path = "/health"
+		}
 		if cfg.StartupDelay == 0 {
 			cfg.StartupDelay = 200 * time.Millisecond
 		}
+		if cfg.CheckInterval == 0 {
+			cfg.CheckInterval = 10 * time.Second
+		}
 	}
 
 	h := &Health{
-		cfg: cfg,
-		log: p.Logger.With(zap.String("component", "health")),
+		gates:  p.Gates,
+		checks: checks,
+		cfg:    cfg,
+		log:    p.Logger.With(zap.String("component", "health")),
 	}
+	stopChecks := func() {}
 
 	// This lifecycle hook is independent of the server and manages the
 	// readiness/liveness state for both Module and MuxModule.
@@ -106,6 +179,15 @@ func New(p Params) *Health {
 		OnStart: func(ctx context.Context) error {
 			h.live.Store(true)
 			h.ready.Store(false)
+			if len(h.checks) > 0 {
+				var checkCtx context.Context
+				checkCtx, stopChecks = context.WithCancel(context.Background())
+				go h.runChecks(checkCtx, h.cfg.CheckInterval)
+			}
+			if h.gated.Load() {
+				// Readiness flips once Fx reports the app fully started.
+				return nil
+			}
 			go func() {
 				time.Sleep(h.cfg.StartupDelay)
 				h.ready.Store(true)
@@ -114,42 +196,90 @@ func New(p Params) *Health {
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			h.ready.Store(false)
+			stopChecks()
+			h.drain(ctx)
 			h.live.Store(false)
 			h.log.Info("service is stopping")
 			return nil
 		},
 	})
 
-	return h
+	return h, nil
+}
+
+// drain reports unready and then waits PreStopDelay, or until ctx is done,
+// while requests keep being served. Only the first call waits.
+func (h *Health) drain(ctx context.Context) {
+	h.drainOnce.Do(func() {
+		h.ready.Store(false)
+		if h.cfg.PreStopDelay <= 0 {
+			return
+		}
+		h.log.Info("service is draining", zap.Duration("pre_stop_delay", h.cfg.PreStopDelay))
+		t := time.NewTimer(h.cfg.PreStopDelay)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+	})
+}
+
+// OnStarted gates readiness on the Fx Started event instead of StartupDelay.
+// Pass the returned callback as fxeventlog.Options.OnStarted; once it has been
+// obtained, the service only reports ready after every OnStart hook has run.
+// If it is never wired, the StartupDelay timer is used.
+func (h *Health) OnStarted() func(error) {
+	h.gated.Store(true)
+	return func(err error) {
+		if err != nil || !h.live.Load() {
+			return
+		}
+		h.ready.Store(true)
+		h.log.Info("service is ready")
+	}
 }
 
 // response is the JSON structure returned by the health endpoint.
 type response struct {
-	Status string `json:"status"`
-	Ready  bool   `json:"ready"`
-	Live   bool   `json:"live"`
+	Status  string   `json:"status"`
+	Ready   bool     `json:"ready"`
+	Live    bool     `json:"live"`
+	Pending []string `json:"pending,omitempty"` // unreleased readiness gates
+	Failing []string `json:"failing,omitempty"` // readiness checks not passing
+	// Checks reports every readiness check with its latest error.
+	Checks []checkStatus `json:"checks,omitempty"`
 }
 
 // handler returns an http.Handler that serves the health status.
-func (h *Health) handler() http.Handler {
+func (h *Health) handler() http.Handler { return h.probeHandler(true) }
+
+// probeHandler serves the health status, answering 503 when the service is
+// not live or, if needReady is set, not ready. The body is the same either
+// way.
+func (h *Health) probeHandler(needReady bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 			return
 		}
 
+		pending := h.pendingGates()
+		failing := h.failingChecks()
 		resp := response{
-			Status: "ok",
-			Live:   h.live.Load(),
-			Ready:  h.ready.Load(),
+			Status:  "ok",
+			Live:    h.live.Load(),
+			Ready:   h.ready.Load() && len(pending) == 0 && len(failing) == 0,
+			Pending: pending,
+			Failing: failing,
+			Checks:  h.checkStatuses(),
 		}
 		code := http.StatusOK
 
 		if !resp.Live {
 			resp.Status = "unhealthy"
 			code = http.StatusServiceUnavailable
-		} else if !resp.Ready {
+		} else if !resp.Ready && needReady {
 			resp.Status = "initializing"
 			code = http.StatusServiceUnavailable
 		}
@@ -163,11 +293,46 @@ func (h *Health) handler() http.Handler {
 	})
 }
 
+// Ready reports whether the service is live and ready: the startup delay (or
+// OnStarted) has passed, every gate is released, and every check passes. It
+// matches the endpoint's `ready` field.
+func (h *Health) Ready() bool {
+	return h.live.Load() && h.ready.Load() && len(h.pendingGates()) == 0 && len(h.failingChecks()) == 0
+}
+
+func readyFunc(h *Health) func() bool { return h.Ready }
+
+func drainFunc(h *Health) func(context.Context) { return h.drain }
+
+// pendingGates returns the names of readiness gates not yet released.
+func (h *Health) pendingGates() []string {
+	var out []string
+	for _, g := range h.gates {
+		if !g.Released() {
+			out = append(out, g.Name())
+		}
+	}
+	return out
+}
+
+// probePatterns returns the endpoints served for h: the configured path, plus
+// /livez and /readyz when separate_probes is set.
+func (h *Health) probePatterns() map[string]http.Handler {
+	out := map[string]http.Handler{h.cfg.Path: h.handler()}
+	if h.cfg.SeparateProbes {
+		out["/livez"] = h.probeHandler(false)
+		out["/readyz"] = h.probeHandler(true)
+	}
+	return out
+}
+
 // RegisterServer creates a dedicated HTTP server and registers it with the
 // application lifecycle. This is used by ServerModule().
 func RegisterServer(lc fx.Lifecycle, h *Health) {
 	mux := http.NewServeMux()
-	mux.Handle("/health", h.handler())
+	for pattern, handler := range h.probePatterns() {
+		mux.Handle(pattern, handler)
+	}
 	server := &http.Server{
 		Addr:    h.cfg.Port,
 		Handler: mux,
@@ -184,6 +349,9 @@ func RegisterServer(lc fx.Lifecycle, h *Health) {
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			// This hook runs before the Health one; keep the server up
+			// through the pre-stop delay.
+			h.drain(ctx)
 			h.log.Info("stopping health server")
 			return server.Shutdown(ctx)
 		},
@@ -193,5 +361,31 @@ func RegisterServer(lc fx.Lifecycle, h *Health) {
 // RegisterMux attaches the health handler to a Mux provided in the Fx container.
 // This is used by MuxModule().
 func RegisterMux(mux *http.ServeMux, h *Health) {
-	mux.Handle("/health", h.handler())
+	for pattern, handler := range h.probePatterns() {
+		mux.Handle(pattern, handler)
+	}
+}
+
+// NewHandler returns the health handler as an httpkit.Handler.
+// This is used by HandlerModule().
+func NewHandler(h *Health) httpkit.Handler {
+	return httpkit.Handler{Pattern: h.cfg.Path, Handler: h.handler()}
+}
+
+// exemptPaths returns the probe paths httpkit's readiness gate must always
+// serve, the configured path included.
+func exemptPaths(h *Health) []string {
+	return slices.Sorted(maps.Keys(h.probePatterns()))
+}
+
+// newProbeHandlers contributes /livez and /readyz to httpkit when
+// separate_probes is set. The health path comes from NewHandler.
+func newProbeHandlers(h *Health) []httpkit.Handler {
+	if !h.cfg.SeparateProbes {
+		return nil
+	}
+	return []httpkit.Handler{
+		{Pattern: "/livez", Handler: h.probeHandler(false)},
+		{Pattern: "/readyz", Handler: h.probeHandler(true)},
+	}
 }
@@ -0,0 +1,56 @@
+package healthkit
+
+import "sync"
+
+// Gate holds readiness open until released. Modules that finish initializing
+// asynchronously (e.g. warming a cache) contribute a Gate to
+// `group:"readiness.gates"` and release it once ready; the health service
+// reports ready only after every gate is released.
+//
+// Since Fx groups cannot be read back by their contributors, construct the
+// gate alongside the service that releases it:
+//
+//	type out struct {
+//	    fx.Out
+//	    Cache *Cache
+//	    Gate  *healthkit.Gate `group:"readiness.gates"`
+//	}
+//
+//	func NewCache() out {
+//	    gate := healthkit.NewGate("cache")
+//	    c := &Cache{}
+//	    go func() { c.warm(); gate.Release() }()
+//	    return out{Cache: c, Gate: gate}
+//	}
+type Gate struct {
+	name string
+	once sync.Once
+	done chan struct{}
+}
+
+// NewGate returns an unreleased gate. name is reported by the health endpoint
+// while the gate is pending.
+func NewGate(name string) *Gate {
+	return &Gate{name: name, done: make(chan struct{})}
+}
+
+// Name returns the gate's name.
+func (g *Gate) Name() string { return g.name }
+
+// Release opens the gate. It is safe to call more than once.
+func (g *Gate) Release() {
+	g.once.Do(func() { close(g.done) })
+}
+
+// Released reports whether Release has been called.
+func (g *Gate) Released() bool {
+	select {
+	case <-g.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns a channel closed once the gate is released.
+func (g *Gate) Done() <-chan struct{} { return g.done }
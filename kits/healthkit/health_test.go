@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/fxeventlog"
 	"github.com/froppa/stackkit/kits/healthkit"
+	"github.com/froppa/stackkit/kits/httpkit"
 	"github.com/stretchr/testify/require"
 	uber "go.uber.org/config"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
 	"go.uber.org/zap"
 )
@@ -171,6 +176,435 @@ func TestHealthModule(t *testing.T) {
 		checkHealthEndpoint(t, healthServerURL, "unhealthy", http.StatusServiceUnavailable, false, false)
 	})
 
+	t.Run("HandlerModule serves via httpkit", func(t *testing.T) {
+		t.Parallel()
+		var ln net.Listener
+
+		yamlSrc := fmt.Sprintf("http:\n  addr: \"127.0.0.1:0\"\nhealth:\n  startup_delay: %s\n", testStartupDelay.String())
+
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			httpkit.Module(),
+			healthkit.HandlerModule(),
+			fx.Populate(&ln),
+		)
+
+		startCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Start(startCtx), "Fx app should start without error")
+
+		healthServerURL := "http://" + ln.Addr().String() + "/health"
+		checkHealthEndpoint(t, healthServerURL, "initializing", http.StatusServiceUnavailable, true, false)
+
+		time.Sleep(testStartupDelay + 10*time.Millisecond)
+		checkHealthEndpoint(t, healthServerURL, "ok", http.StatusOK, true, true)
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(stopCtx), "Fx app should stop without error")
+	})
+
+	t.Run("OnStarted gates readiness on fx start", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+		healthServerURL := testServer.URL + "/health"
+
+		yamlSrc := fmt.Sprintf("health:\n  startup_delay: %s\n", testStartupDelay.String())
+
+		app := fxtest.New(t,
+			fx.WithLogger(func(h *healthkit.Health) fxevent.Logger {
+				opts := fxeventlog.DefaultOptions
+				opts.OnStarted = h.OnStarted()
+				return fxeventlog.NewWithOptions(zap.NewNop(), opts)
+			}),
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+			// A slow OnStart hook registered after health: the startup delay
+			// elapses while the app is still starting.
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{OnStart: func(context.Context) error {
+					time.Sleep(testStartupDelay + 50*time.Millisecond)
+					checkHealthEndpoint(t, healthServerURL, "initializing", http.StatusServiceUnavailable, true, false)
+					return nil
+				}})
+			}),
+		)
+
+		startCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Start(startCtx), "Fx app should start without error")
+
+		// Ready as soon as Start returns, without waiting for the timer.
+		checkHealthEndpoint(t, healthServerURL, "ok", http.StatusOK, true, true)
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(stopCtx), "Fx app should stop without error")
+	})
+
+	t.Run("readiness gates hold readiness until released", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+		healthServerURL := testServer.URL + "/health"
+
+		yamlSrc := fmt.Sprintf("health:\n  startup_delay: %s\n", testStartupDelay.String())
+		gate := healthkit.NewGate("cache")
+
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			fx.Provide(fx.Annotate(func() *healthkit.Gate { return gate }, fx.ResultTags(`group:"readiness.gates"`))),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+
+		startCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Start(startCtx), "Fx app should start without error")
+
+		// Startup delay has passed, but the gate is still held.
+		time.Sleep(testStartupDelay + 10*time.Millisecond)
+		checkHealthEndpoint(t, healthServerURL, "initializing", http.StatusServiceUnavailable, true, false)
+
+		gate.Release()
+		checkHealthEndpoint(t, healthServerURL, "ok", http.StatusOK, true, true)
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(stopCtx), "Fx app should stop without error")
+	})
+
+	t.Run("readiness checks flip after consecutive results", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+		healthServerURL := testServer.URL + "/health"
+
+		yamlSrc := "health:\n  startup_delay: 10ms\n  check_interval: 20ms\n"
+		var healthy, probes atomic.Int32
+		healthy.Store(1)
+		check := &healthkit.Check{
+			Name: "db",
+			Probe: func(context.Context) error {
+				probes.Add(1)
+				if healthy.Load() == 0 {
+					return errors.New("down")
+				}
+				return nil
+			},
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+		}
+
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			fx.Provide(fx.Annotate(func() *healthkit.Check { return check }, fx.ResultTags(`group:"readiness.checks"`))),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+
+		startCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Start(startCtx), "Fx app should start without error")
+		require.Eventually(t, check.Passing, time.Second, 5*time.Millisecond)
+		time.Sleep(20 * time.Millisecond) // past startup_delay
+		checkHealthEndpoint(t, healthServerURL, "ok", http.StatusOK, true, true)
+
+		// A failing probe only counts after FailureThreshold results in a row.
+		healthy.Store(0)
+		start := probes.Load()
+		require.Eventually(t, func() bool { return !check.Passing() }, time.Second, 5*time.Millisecond)
+		require.GreaterOrEqual(t, probes.Load()-start, int32(3))
+		checkHealthEndpoint(t, healthServerURL, "initializing", http.StatusServiceUnavailable, true, false)
+
+		healthy.Store(1)
+		start = probes.Load()
+		require.Eventually(t, check.Passing, time.Second, 5*time.Millisecond)
+		require.GreaterOrEqual(t, probes.Load()-start, int32(2))
+		checkHealthEndpoint(t, healthServerURL, "ok", http.StatusOK, true, true)
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(stopCtx), "Fx app should stop without error")
+	})
+
+	t.Run("health.checks report a per-check breakdown", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+
+		yamlSrc := "health:\n  startup_delay: 10ms\n  check_interval: 20ms\n"
+		slow := &healthkit.Check{
+			Name:    "slow",
+			Timeout: 5 * time.Millisecond,
+			Probe: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		ok := &healthkit.Check{Name: "cache", Probe: func(context.Context) error { return nil }}
+
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			fx.Provide(fx.Annotate(func() *healthkit.Check { return slow }, fx.ResultTags(`group:"health.checks"`))),
+			fx.Provide(fx.Annotate(func() *healthkit.Check { return ok }, fx.ResultTags(`group:"health.checks"`))),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+		require.Eventually(t, ok.Passing, time.Second, 5*time.Millisecond)
+		time.Sleep(20 * time.Millisecond) // past startup_delay
+
+		res, err := http.Get(testServer.URL + "/health")
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		var body struct {
+			Live    bool     `json:"live"`
+			Ready   bool     `json:"ready"`
+			Failing []string `json:"failing"`
+			Checks  []struct {
+				Name    string `json:"name"`
+				Passing bool   `json:"passing"`
+				Error   string `json:"error"`
+			} `json:"checks"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.True(t, body.Live, "liveness ignores checks")
+		require.False(t, body.Ready)
+		require.Equal(t, []string{"slow"}, body.Failing)
+		require.Len(t, body.Checks, 2)
+		for _, c := range body.Checks {
+			switch c.Name {
+			case "slow":
+				require.False(t, c.Passing)
+				require.Equal(t, context.DeadlineExceeded.Error(), c.Error)
+			case "cache":
+				require.True(t, c.Passing)
+				require.Empty(t, c.Error)
+			}
+		}
+	})
+
+	t.Run("a hung probe does not block other checks", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+		yamlSrc := "health:\n  check_interval: 20ms\n"
+		hung := &healthkit.Check{
+			Name:    "hung",
+			Timeout: 5 * time.Millisecond,
+			Probe: func(context.Context) error {
+				<-release // ignores its context
+				return nil
+			},
+		}
+		ok := &healthkit.Check{Name: "cache", Probe: func(context.Context) error { return nil }}
+
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(http.NewServeMux),
+			fx.Provide(fx.Annotate(func() *healthkit.Check { return hung }, fx.ResultTags(`group:"health.checks"`))),
+			fx.Provide(fx.Annotate(func() *healthkit.Check { return ok }, fx.ResultTags(`group:"health.checks"`))),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+		require.Eventually(t, ok.Passing, time.Second, 5*time.Millisecond)
+		require.Eventually(t, func() bool { return !hung.Passing() }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("checks without a name or probe are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		for name, check := range map[string]*healthkit.Check{
+			"no name":  {Probe: func(context.Context) error { return nil }},
+			"no probe": {Name: "db"},
+		} {
+			app := fx.New(
+				fx.NopLogger,
+				fx.Provide(zap.NewNop),
+				fx.Provide(http.NewServeMux),
+				fx.Provide(fx.Annotate(func() *healthkit.Check { return check }, fx.ResultTags(`group:"readiness.checks"`))),
+				configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString("health: {}\n")))),
+				healthkit.MuxModule(),
+			)
+			require.Error(t, app.Err(), name)
+		}
+	})
+
+	t.Run("separate_probes serves livez and readyz", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+
+		gate := healthkit.NewGate("warmup")
+		yamlSrc := "health:\n  startup_delay: 10ms\n  separate_probes: true\n"
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			fx.Provide(fx.Annotate(func() *healthkit.Gate { return gate }, fx.ResultTags(`group:"readiness.gates"`))),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		app.RequireStart()
+		time.Sleep(30 * time.Millisecond) // past startup_delay
+
+		// Live but held unready by the gate.
+		checkHealthEndpoint(t, testServer.URL+"/livez", "ok", http.StatusOK, true, false)
+		checkHealthEndpoint(t, testServer.URL+"/readyz", "initializing", http.StatusServiceUnavailable, true, false)
+		checkHealthEndpoint(t, testServer.URL+"/health", "initializing", http.StatusServiceUnavailable, true, false)
+
+		gate.Release()
+		checkHealthEndpoint(t, testServer.URL+"/readyz", "ok", http.StatusOK, true, true)
+
+		app.RequireStop()
+		checkHealthEndpoint(t, testServer.URL+"/livez", "unhealthy", http.StatusServiceUnavailable, false, false)
+	})
+
+	t.Run("pre_stop_delay reports unready before not live", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+		url := testServer.URL + "/health"
+
+		yamlSrc := "health:\n  startup_delay: 10ms\n  pre_stop_delay: 300ms\n"
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		app.RequireStart()
+		time.Sleep(30 * time.Millisecond) // past startup_delay
+		checkHealthEndpoint(t, url, "ok", http.StatusOK, true, true)
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			app.RequireStop()
+		}()
+
+		// Still live, but no longer ready, while the delay runs.
+		time.Sleep(50 * time.Millisecond)
+		checkHealthEndpoint(t, url, "initializing", http.StatusServiceUnavailable, true, false)
+
+		<-stopped
+		checkHealthEndpoint(t, url, "unhealthy", http.StatusServiceUnavailable, false, false)
+	})
+
+	t.Run("pre_stop_delay keeps httpkit's server serving", func(t *testing.T) {
+		t.Parallel()
+		var ln net.Listener
+
+		yamlSrc := "http:\n  addr: \"127.0.0.1:0\"\nhealth:\n  startup_delay: 10ms\n  pre_stop_delay: 300ms\n"
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			httpkit.Module(),
+			healthkit.HandlerModule(),
+			fx.Populate(&ln),
+		)
+		app.RequireStart()
+		url := "http://" + ln.Addr().String() + "/health"
+		time.Sleep(30 * time.Millisecond) // past startup_delay
+		checkHealthEndpoint(t, url, "ok", http.StatusOK, true, true)
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			app.RequireStop()
+		}()
+
+		// httpkit's stop hook drains first, so the server still answers.
+		time.Sleep(50 * time.Millisecond)
+		checkHealthEndpoint(t, url, "initializing", http.StatusServiceUnavailable, true, false)
+
+		<-stopped
+		_, err := http.Get(url)
+		require.Error(t, err)
+	})
+
+	t.Run("path moves the health endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		mux := http.NewServeMux()
+		testServer := httptest.NewServer(mux)
+		defer testServer.Close()
+
+		yamlSrc := "health:\n  startup_delay: 10ms\n  path: /_status\n"
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			fx.Provide(func() *http.ServeMux { return mux }),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+		time.Sleep(30 * time.Millisecond) // past startup_delay
+
+		checkHealthEndpoint(t, testServer.URL+"/_status", "ok", http.StatusOK, true, true)
+		res, err := http.Get(testServer.URL + "/health")
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("path is exempt from httpkit's readiness gate", func(t *testing.T) {
+		t.Parallel()
+		var ln net.Listener
+
+		yamlSrc := "http:\n  addr: \"127.0.0.1:0\"\n  readiness_gate: true\nhealth:\n  startup_delay: 1h\n  path: /_status\n"
+		app := fxtest.New(t,
+			fx.Provide(zap.NewNop),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			httpkit.Module(),
+			healthkit.HandlerModule(),
+			fx.Populate(&ln),
+		)
+		app.RequireStart()
+		defer app.RequireStop()
+
+		// The health handler answers, not the gate.
+		checkHealthEndpoint(t, "http://"+ln.Addr().String()+"/_status", "initializing", http.StatusServiceUnavailable, true, false)
+	})
+
+	t.Run("path must start with a slash", func(t *testing.T) {
+		t.Parallel()
+
+		yamlSrc := "health:\n  path: status\n"
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(zap.NewNop),
+			fx.Provide(http.NewServeMux),
+			configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString(yamlSrc)))),
+			healthkit.MuxModule(),
+		)
+		require.ErrorContains(t, app.Err(), "startswith")
+	})
+
 	t.Run("ServerModule works with default config", func(t *testing.T) {
 		t.Parallel()
 
@@ -194,3 +628,37 @@ func TestHealthModule(t *testing.T) {
 		require.NoError(t, app.Stop(stopCtx), "Fx app should stop without error with default config")
 	})
 }
+
+func TestRetryUntilReady(t *testing.T) {
+	t.Run("succeeds after failures", func(t *testing.T) {
+		var calls atomic.Int32
+		err := healthkit.RetryUntilReady(context.Background(), 5, time.Millisecond, func(context.Context) error {
+			if calls.Add(1) < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		boom := errors.New("down")
+		var calls atomic.Int32
+		err := healthkit.RetryUntilReady(context.Background(), 3, time.Millisecond, func(context.Context) error {
+			calls.Add(1)
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("stops when context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := healthkit.RetryUntilReady(ctx, 0, 10*time.Millisecond, func(context.Context) error {
+			return errors.New("down")
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,59 @@
+package healthkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// maxRetryBackoff caps the delay between RetryUntilReady attempts.
+const maxRetryBackoff = 30 * time.Second
+
+// RetryUntilReady calls fn until it returns nil, sleeping between attempts
+// with exponential backoff: the delay starts at backoff and doubles up to 30s,
+// and each sleep is drawn at random from the upper half of the delay.
+// attempts <= 0 retries until ctx is done; backoff <= 0 selects 100ms.
+//
+// It returns nil on success, or an error wrapping the last failure once the
+// attempts run out or ctx is done. Callers typically release a Gate on
+// success:
+//
+//	go func() {
+//	    if err := healthkit.RetryUntilReady(ctx, 10, 200*time.Millisecond, db.PingContext); err != nil {
+//	        log.Error("database unreachable", zap.Error(err))
+//	        return
+//	    }
+//	    gate.Release()
+//	}()
+func RetryUntilReady(ctx context.Context, attempts int, backoff time.Duration, fn func(context.Context) error) error {
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	delay := backoff
+	for n := 1; ; n++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempts > 0 && n >= attempts {
+			return fmt.Errorf("healthkit: gave up after %d attempts: %w", n, err)
+		}
+
+		timer := time.NewTimer(jitter(delay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("healthkit: gave up after %d attempts: %w", n, errors.Join(err, ctx.Err()))
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxRetryBackoff)
+	}
+}
+
+// jitter returns a random duration in [d/2, d].
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
@@ -0,0 +1,50 @@
+package httpkit
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderAttributes returns middleware that copies the configured request
+// headers onto telemetry as attributes. mapping goes from header name (any
+// case) to attribute key, e.g. {"X-Tenant-ID": "tenant.id"}; only those
+// headers are read, which keeps attribute cardinality under your control.
+// Headers that are absent or empty are skipped.
+//
+// The attributes are set on the span active when the request reaches the
+// middleware and added to the otelhttp metric Labeler, which otelhttp
+// handlers further down reuse for their request metrics. Used on its own, it
+// needs an otelhttp handler (or another span) around it; Config's
+// header_attributes wraps it in one named ServerSpanName.
+func HeaderAttributes(mapping map[string]string) func(http.Handler) http.Handler {
+	headers := make(map[string]string, len(mapping))
+	for h, key := range mapping {
+		headers[http.CanonicalHeaderKey(h)] = key
+	}
+	return func(next http.Handler) http.Handler {
+		if len(headers) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var attrs []attribute.KeyValue
+			for h, key := range headers {
+				if v := r.Header.Get(h); v != "" {
+					attrs = append(attrs, attribute.String(key, v))
+				}
+			}
+			if len(attrs) > 0 {
+				ctx := r.Context()
+				trace.SpanFromContext(ctx).SetAttributes(attrs...)
+				labeler, ok := otelhttp.LabelerFromContext(ctx)
+				if !ok {
+					r = r.WithContext(otelhttp.ContextWithLabeler(ctx, labeler))
+				}
+				labeler.Add(attrs...)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package httpkit_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	httpfx "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func TestModule_HeaderAttributesOnServerSpanAndMetrics(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	prevTP, prevMP := otel.GetTracerProvider(), otel.GetMeterProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetMeterProvider(prevMP)
+	})
+
+	var port int
+	app := fxtest.New(t,
+		fx.Replace(&httpfx.Config{
+			Addr: "127.0.0.1:0",
+			HeaderAttributes: map[string]string{
+				"x-tenant-id": "tenant.id",
+				"X-Region":    "region",
+			},
+		}),
+		fx.Provide(zap.NewNop),
+		fx.Provide(fx.Annotate(func() httpfx.Handler {
+			return httpfx.Handler{Pattern: "/ping", Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})}
+		}, fx.ResultTags(`group:"http.handlers"`))),
+		httpfx.Module(),
+		fx.Invoke(func(l net.Listener) { port = l.Addr().(*net.TCPAddr).Port }),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:"+strconv.Itoa(port)+"/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Tenant-Id", "acme")
+	req.Header.Set("X-Unmapped", "ignored")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	var server sdktrace.ReadOnlySpan
+	require.Eventually(t, func() bool {
+		for _, s := range spans.Ended() {
+			if s.Name() == httpfx.ServerSpanName {
+				server = s
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Contains(t, server.Attributes(), attribute.String("tenant.id", "acme"))
+	for _, kv := range server.Attributes() {
+		require.NotEqual(t, attribute.Key("region"), kv.Key, "absent headers are skipped")
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				continue
+			}
+			for _, dp := range hist.DataPoints {
+				if v, ok := dp.Attributes.Value("tenant.id"); ok && v.AsString() == "acme" {
+					found = true
+				}
+			}
+		}
+	}
+	require.True(t, found, "request metrics carry the header attribute")
+}
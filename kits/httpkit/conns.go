@@ -0,0 +1,43 @@
+package httpkit
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// connTracker counts the server's open connections via http.Server.ConnState,
+// so shutdown can report how many were drained or cut.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]http.ConnState
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: map[net.Conn]http.ConnState{}}
+}
+
+// track is an http.Server.ConnState hook.
+func (t *connTracker) track(c net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(t.conns, c)
+	default:
+		t.conns[c] = state
+	}
+}
+
+// counts returns the number of open connections and how many of them are
+// serving a request.
+func (t *connTracker) counts() (open, active int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.conns {
+		if s == http.StateActive {
+			active++
+		}
+	}
+	return len(t.conns), active
+}
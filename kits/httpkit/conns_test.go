@@ -0,0 +1,65 @@
+package httpkit_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	httpfx "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestShutdownReportsConnections(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	var port int
+	app := fx.New(
+		fx.NopLogger,
+		fx.Replace(&httpfx.Config{Addr: "127.0.0.1:0"}),
+		fx.Provide(func() *zap.Logger { return zap.New(core) }),
+		fx.Provide(fx.Annotate(
+			func() httpfx.Handler {
+				return httpfx.Handler{Pattern: "GET /slow", Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					close(entered)
+					<-release
+				})}
+			},
+			fx.ResultTags(`group:"http.handlers"`),
+		)),
+		httpfx.Module(),
+		fx.Invoke(func(l net.Listener) { port = l.Addr().(*net.TCPAddr).Port }),
+	)
+	require.NoError(t, app.Start(context.Background()))
+
+	go func() {
+		resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/slow")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// Fx returns at the deadline; the hook logs right after.
+	_ = app.Stop(ctx)
+
+	stop := logs.FilterMessage("http.stop").All()
+	require.Len(t, stop, 1)
+	require.Equal(t, int64(1), stop[0].ContextMap()["active_conns"])
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("http.shutdown_timeout").Len() == 1
+	}, time.Second, 5*time.Millisecond)
+	timeout := logs.FilterMessage("http.shutdown_timeout").All()
+	require.Equal(t, int64(1), timeout[0].ContextMap()["force_closed"])
+}
@@ -0,0 +1,78 @@
+package httpkit
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ErrorResponse is the JSON body written by WriteError.
+type ErrorResponse struct {
+	Status  int    `json:"status"`
+	Error   string `json:"error"`   // status text, e.g. "Not Found"
+	Message string `json:"message"` // human-readable detail
+}
+
+// WriteError writes a JSON ErrorResponse with the given status. An empty msg
+// defaults to the status text.
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Status: status, Error: http.StatusText(status), Message: msg})
+}
+
+// withFallbacks serves unmatched requests through notFound (404) and
+// WriteError (405, keeping the Allow header) instead of the ServeMux's plain
+// text responses. ServeMux has no hook for this, so each request is first
+// resolved with mux.Handler and only unmatched ones are intercepted.
+func withFallbacks(mux *http.ServeMux, notFound http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		// Let the mux decide between 404, 405, and canonical-path redirects.
+		iw := &interceptWriter{ResponseWriter: w}
+		h.ServeHTTP(iw, r)
+		switch iw.status {
+		case http.StatusNotFound:
+			notFound.ServeHTTP(w, r)
+		case http.StatusMethodNotAllowed:
+			WriteError(w, http.StatusMethodNotAllowed, "")
+		}
+	})
+}
+
+// interceptWriter swallows 404 and 405 responses so they can be replaced;
+// anything else is passed through.
+type interceptWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *interceptWriter) WriteHeader(code int) {
+	if code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		w.status = code
+		// The plain-text defaults set these; the replacement sets its own.
+		w.Header().Del("Content-Type")
+		w.Header().Del("X-Content-Type-Options")
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *interceptWriter) Write(p []byte) (int, error) {
+	if w.status != 0 {
+		return len(p), nil
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func defaultNotFound(w http.ResponseWriter, r *http.Request) {
+	WriteError(w, http.StatusNotFound, "no route for "+r.URL.Path)
+}
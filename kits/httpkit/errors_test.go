@@ -0,0 +1,108 @@
+package httpkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	httpfx "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+func TestWriteError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	httpfx.WriteError(rr, http.StatusTeapot, "")
+	require.Equal(t, http.StatusTeapot, rr.Code)
+	require.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	var body httpfx.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	require.Equal(t, httpfx.ErrorResponse{Status: 418, Error: "I'm a teapot", Message: "I'm a teapot"}, body)
+}
+
+// startServer runs httpkit.Module with cfg and a GET /ping route and returns
+// the base URL.
+func startServer(t *testing.T, cfg httpfx.Config, opts ...fx.Option) string {
+	t.Helper()
+	cfg.Addr = "127.0.0.1:0"
+	var port int
+	app := fx.New(append([]fx.Option{
+		fx.NopLogger,
+		fx.Replace(&cfg),
+		fx.Provide(zap.NewNop),
+		fx.Provide(fx.Annotate(
+			func() httpfx.Handler {
+				return httpfx.Handler{Pattern: "GET /ping", Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = io.WriteString(w, "pong")
+				})}
+			},
+			fx.ResultTags(`group:"http.handlers"`),
+		)),
+		httpfx.Module(),
+		fx.Invoke(func(l net.Listener) { port = l.Addr().(*net.TCPAddr).Port }),
+	}, opts...)...)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, app.Start(ctx))
+	t.Cleanup(func() { _ = app.Stop(context.Background()) })
+	return "http://127.0.0.1:" + strconv.Itoa(port)
+}
+
+func do(t *testing.T, method, url string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp, string(b)
+}
+
+func TestFallbacks_DefaultIsPlainText(t *testing.T) {
+	base := startServer(t, httpfx.Config{})
+	resp, body := do(t, http.MethodGet, base+"/nope")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, "404 page not found\n", body)
+}
+
+func TestFallbacks_JSONErrors(t *testing.T) {
+	base := startServer(t, httpfx.Config{JSONErrors: true})
+
+	resp, body := do(t, http.MethodGet, base+"/ping")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "pong", body)
+
+	resp, body = do(t, http.MethodGet, base+"/nope")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+	require.JSONEq(t, `{"status":404,"error":"Not Found","message":"no route for /nope"}`, body)
+
+	resp, body = do(t, http.MethodPost, base+"/ping")
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Allow"), "GET")
+	require.JSONEq(t, `{"status":405,"error":"Method Not Allowed","message":"Method Not Allowed"}`, body)
+}
+
+func TestFallbacks_CustomNotFoundHandler(t *testing.T) {
+	custom := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		httpfx.WriteError(w, http.StatusNotFound, "try /ping")
+	})
+	base := startServer(t, httpfx.Config{}, fx.Provide(fx.Annotate(
+		func() http.Handler { return custom },
+		fx.ResultTags(`name:"`+httpfx.NotFoundHandlerName+`"`),
+	)))
+
+	resp, body := do(t, http.MethodGet, base+"/nope")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.JSONEq(t, `{"status":404,"error":"Not Found","message":"try /ping"}`, body)
+}
@@ -10,10 +10,12 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"slices"
 	"time"
 
 	"github.com/froppa/stackkit/kits/configkit"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -33,19 +35,71 @@ type Config struct {
 
 	// EnablePprof enables /debug/pprof endpoints if true. Default false.
 	EnablePprof bool `yaml:"enable_pprof"`
+
+	// Compression gzips responses for clients that accept it. Default false.
+	Compression bool `yaml:"compression"`
+
+	// CompressionMinBytes is the smallest body that gets compressed.
+	// Defaults to DefaultCompressionMinBytes when 0.
+	CompressionMinBytes int `yaml:"compression_min_bytes" validate:"gte=0"`
+
+	// JSONErrors answers unmatched routes (404) and wrong methods (405) with
+	// WriteError's JSON body instead of ServeMux's plain text. Default false.
+	JSONErrors bool `yaml:"json_errors"`
+
+	// HeaderAttributes maps request header names to telemetry attribute keys,
+	// e.g. {"X-Tenant-ID": "tenant.id"}. See HeaderAttributes.
+	HeaderAttributes map[string]string `yaml:"header_attributes" validate:"omitempty,dive,keys,required,endkeys,required"`
+
+	// ReadinessGate answers 503 while the readiness func provided as
+	// `name:"http.ready"` reports false (see ReadinessGate), except for
+	// DefaultReadinessExempt, ReadinessExempt, and paths added through
+	// ReadinessExemptGroup. It has no effect without
+	// that func. Default false.
+	ReadinessGate bool `yaml:"readiness_gate"`
+
+	// ReadinessExempt lists further paths the readiness gate always serves,
+	// e.g. a health endpoint moved off /health.
+	ReadinessExempt []string `yaml:"readiness_exempt" validate:"omitempty,dive,startswith=/"`
 }
 
 // Handler allows services to register additional HTTP routes via Fx groups.
 type Handler struct {
 	Pattern string
 	Handler http.Handler
+	// Prefix, if set, is inserted before Pattern's path, after any method
+	// and host: Prefix "/v1" with Pattern "GET /users" serves "GET /v1/users".
+	// The handler sees the full, unstripped path. See Group.
+	Prefix string
 }
 
+// NotFoundHandlerName is the Fx name under which a custom not-found handler
+// can be provided:
+//
+//	fx.Provide(fx.Annotate(myHandler, fx.ResultTags(`name:"http.not_found"`)))
+//
+// When present it serves every unmatched route, and 405 responses switch to
+// WriteError's JSON body, regardless of Config.JSONErrors.
+const NotFoundHandlerName = "http.not_found"
+
+// DrainFuncName is the Fx name under which a drain func can be provided. The
+// server's stop hook calls it before shutting down, so the server keeps
+// serving while it runs:
+//
+//	fx.Provide(fx.Annotate(func(h *healthkit.Health) func(context.Context) { ... },
+//	    fx.ResultTags(`name:"http.drain"`)))
+//
+// healthkit.HandlerModule provides it to honour its pre_stop_delay.
+const DrainFuncName = "http.drain"
+
 // Params is used by NewMux to pull in grouped handlers.
 type Params struct {
 	fx.In
 	Cfg      *Config
 	Handlers []Handler `group:"http.handlers"`
+	// Propagator, provided by telemetry.Module, replaces the otel global
+	// propagator in the pprof handlers' instrumentation.
+	Propagator propagation.TextMapPropagator `optional:"true"`
 }
 
 // Module provides HTTP server configuration and lifecycle management for Fx.
@@ -90,31 +144,79 @@ func NewMux(p Params) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	if p.Cfg.EnablePprof {
-		mux.Handle("/debug/pprof/", otelhttp.NewHandler(http.HandlerFunc(pprof.Index), "pprof.index"))
-		mux.Handle("/debug/pprof/cmdline", otelhttp.NewHandler(http.HandlerFunc(pprof.Cmdline), "pprof.cmdline"))
-		mux.Handle("/debug/pprof/profile", otelhttp.NewHandler(http.HandlerFunc(pprof.Profile), "pprof.profile"))
-		mux.Handle("/debug/pprof/symbol", otelhttp.NewHandler(http.HandlerFunc(pprof.Symbol), "pprof.symbol"))
-		mux.Handle("/debug/pprof/trace", otelhttp.NewHandler(http.HandlerFunc(pprof.Trace), "pprof.trace"))
+		var opts []otelhttp.Option
+		if p.Propagator != nil {
+			opts = append(opts, otelhttp.WithPropagators(p.Propagator))
+		}
+		mux.Handle("/debug/pprof/", otelhttp.NewHandler(http.HandlerFunc(pprof.Index), "pprof.index", opts...))
+		mux.Handle("/debug/pprof/cmdline", otelhttp.NewHandler(http.HandlerFunc(pprof.Cmdline), "pprof.cmdline", opts...))
+		mux.Handle("/debug/pprof/profile", otelhttp.NewHandler(http.HandlerFunc(pprof.Profile), "pprof.profile", opts...))
+		mux.Handle("/debug/pprof/symbol", otelhttp.NewHandler(http.HandlerFunc(pprof.Symbol), "pprof.symbol", opts...))
+		mux.Handle("/debug/pprof/trace", otelhttp.NewHandler(http.HandlerFunc(pprof.Trace), "pprof.trace", opts...))
 	}
 
 	for _, r := range p.Handlers {
-		mux.Handle(r.Pattern, r.Handler)
+		mux.Handle(r.pattern(), r.Handler)
 	}
 
 	return mux
 }
 
+// ServerSpanName names the span httpkit starts for every request when
+// Config.HeaderAttributes is set, so the attributes have a span to land on.
+const ServerSpanName = "http.server"
+
+// newHandler wraps mux in the middleware enabled by cfg.
+func newHandler(cfg *Config, mux *http.ServeMux, notFound http.Handler, ready func() bool, exempt []string, prop propagation.TextMapPropagator) http.Handler {
+	var h http.Handler = mux
+	if notFound == nil && cfg.JSONErrors {
+		notFound = http.HandlerFunc(defaultNotFound)
+	}
+	if notFound != nil {
+		h = withFallbacks(mux, notFound)
+	}
+	if cfg.Compression {
+		h = Gzip(cfg.CompressionMinBytes)(h)
+	}
+	if len(cfg.HeaderAttributes) > 0 {
+		// Start the server span outside the middleware, so it has one to
+		// annotate and handlers further in inherit the attributes' labeler.
+		var opts []otelhttp.Option
+		if prop != nil {
+			opts = append(opts, otelhttp.WithPropagators(prop))
+		}
+		h = otelhttp.NewHandler(HeaderAttributes(cfg.HeaderAttributes)(h), ServerSpanName, opts...)
+	}
+	if cfg.ReadinessGate && ready != nil {
+		paths := slices.Concat(DefaultReadinessExempt, cfg.ReadinessExempt, exempt)
+		h = ReadinessGate(ready, paths...)(h)
+	}
+	return h
+}
+
+type serverParams struct {
+	fx.In
+	LC       fx.Lifecycle
+	Listener net.Listener
+	Cfg      *Config
+	Mux      *http.ServeMux
+	Log      *zap.Logger
+	NotFound http.Handler          `name:"http.not_found" optional:"true"`
+	Ready    func() bool           `name:"http.ready" optional:"true"`
+	Drain    func(context.Context) `name:"http.drain" optional:"true"`
+	Exempt   []string              `group:"http.readiness_exempt"`
+	// Propagator, provided by telemetry.Module, is used by the server span.
+	Propagator propagation.TextMapPropagator `optional:"true"`
+}
+
 // registerHTTPServer wires the HTTP server into the Fx lifecycle.
-func registerHTTPServer(
-	lc fx.Lifecycle,
-	listener net.Listener,
-	cfg *Config,
-	mux *http.ServeMux,
-	log *zap.Logger,
-) {
+func registerHTTPServer(p serverParams) {
+	lc, listener, cfg, log := p.LC, p.Listener, p.Cfg, p.Log
+	conns := newConnTracker()
 	srv := &http.Server{
-		Addr:    listener.Addr().String(),
-		Handler: mux,
+		Addr:      listener.Addr().String(),
+		Handler:   newHandler(cfg, p.Mux, p.NotFound, p.Ready, p.Exempt, p.Propagator),
+		ConnState: conns.track,
 	}
 	if cfg.ReadTimeoutMS > 0 {
 		srv.ReadTimeout = time.Duration(cfg.ReadTimeoutMS) * time.Millisecond
@@ -134,15 +236,24 @@ func registerHTTPServer(
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			log.Info("http.stop")
+			if p.Drain != nil {
+				p.Drain(ctx)
+			}
+			open, active := conns.counts()
+			log.Info("http.stop", zap.Int("open_conns", open), zap.Int("active_conns", active))
 			if err := srv.Shutdown(ctx); err != nil {
 				if errors.Is(err, context.DeadlineExceeded) {
-					log.Warn("http.shutdown_timeout")
+					// Whatever Shutdown could not drain is cut by Close.
+					remaining, _ := conns.counts()
+					log.Warn("http.shutdown_timeout",
+						zap.Int("force_closed", remaining),
+						zap.Int("drained", max(open-remaining, 0)),
+					)
 					return srv.Close()
 				}
 				return err
 			}
-			log.Info("http.stopped_clean")
+			log.Info("http.stopped_clean", zap.Int("drained", open))
 			return nil
 		},
 	})
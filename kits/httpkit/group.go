@@ -0,0 +1,53 @@
+package httpkit
+
+import "strings"
+
+// Group returns handlers with prefix prepended to each pattern, for mounting
+// a set of routes under a shared path such as an API version. Contribute the
+// result to the handler group with flatten:
+//
+//	fx.Provide(fx.Annotate(
+//	    func(h *Orders) []httpkit.Handler {
+//	        return httpkit.Group("/v1", httpkit.Handler{Pattern: "GET /orders/{id}", Handler: h})
+//	    },
+//	    fx.ResultTags(`group:"http.handlers,flatten"`),
+//	))
+//
+// Groups nest: a handler that already has a Prefix keeps it below the new one.
+func Group(prefix string, handlers ...Handler) []Handler {
+	out := make([]Handler, len(handlers))
+	for i, h := range handlers {
+		h.Prefix = joinPrefix(prefix, h.Prefix)
+		out[i] = h
+	}
+	return out
+}
+
+// pattern returns the ServeMux pattern for h: Pattern with Prefix inserted
+// before the path, after any method and host.
+func (h Handler) pattern() string {
+	prefix := joinPrefix(h.Prefix, "")
+	if prefix == "" {
+		return h.Pattern
+	}
+	method, rest := "", h.Pattern
+	if m, r, ok := strings.Cut(rest, " "); ok && !strings.Contains(m, "/") {
+		method, rest = m+" ", strings.TrimLeft(r, " \t")
+	}
+	host, path := "", rest
+	if i := strings.IndexByte(rest, '/'); i > 0 {
+		host, path = rest[:i], rest[i:]
+	}
+	return method + host + prefix + path
+}
+
+// joinPrefix joins two path prefixes into one with a leading slash and no
+// trailing slash, or "" if both are empty.
+func joinPrefix(a, b string) string {
+	p := strings.Trim(a, "/") + "/" + strings.Trim(b, "/")
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
@@ -0,0 +1,137 @@
+package httpkit_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpfx "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+)
+
+func serveGzip(h http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rr := httptest.NewRecorder()
+	httpfx.Gzip(64)(h).ServeHTTP(rr, req)
+	return rr
+}
+
+func gunzip(t *testing.T, b []byte) string {
+	t.Helper()
+	zr, err := gzip.NewReader(strings.NewReader(string(b)))
+	require.NoError(t, err)
+	out, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestGzip_Negotiation(t *testing.T) {
+	body := `{"items":"` + strings.Repeat("x", 200) + `"}`
+	h := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body[:50])
+		_, _ = io.WriteString(w, body[50:])
+	}
+
+	rr := serveGzip(h, "deflate, gzip;q=0.8")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	require.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	require.Equal(t, body, gunzip(t, rr.Body.Bytes()))
+
+	for _, ae := range []string{"", "br", "gzip;q=0"} {
+		rr = serveGzip(h, ae)
+		require.Empty(t, rr.Header().Get("Content-Encoding"), ae)
+		require.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+		require.Equal(t, body, rr.Body.String())
+	}
+}
+
+func TestGzip_SkipsSmallAndCompressedBodies(t *testing.T) {
+	small := serveGzip(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "pong")
+	}, "gzip")
+	require.Empty(t, small.Header().Get("Content-Encoding"))
+	require.Equal(t, "pong", small.Body.String())
+	require.Equal(t, "text/plain; charset=utf-8", small.Header().Get("Content-Type"))
+
+	big := strings.Repeat("y", 500)
+	png := serveGzip(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = io.WriteString(w, big)
+	}, "gzip")
+	require.Empty(t, png.Header().Get("Content-Encoding"))
+	require.Equal(t, big, png.Body.String())
+
+	encoded := serveGzip(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = io.WriteString(w, big)
+	}, "gzip")
+	require.Equal(t, "br", encoded.Header().Get("Content-Encoding"))
+	require.Equal(t, big, encoded.Body.String())
+
+	noContent := serveGzip(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, "gzip")
+	require.Equal(t, http.StatusNoContent, noContent.Code)
+	require.Empty(t, noContent.Header().Get("Content-Encoding"))
+}
+
+// statusRecorder stands in for logging/metrics middleware wrapped around Gzip.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(p []byte) (int, error) {
+	n, err := s.ResponseWriter.Write(p)
+	s.bytes += n
+	return n, err
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
+
+func TestGzip_ComposesWithOuterMiddleware(t *testing.T) {
+	body := strings.Repeat("z", 4096)
+	inner := httpfx.Gzip(64)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, body)
+		// Streaming handlers can still flush through the wrapper.
+		require.NoError(t, http.NewResponseController(w).Flush())
+	}))
+
+	var rec *statusRecorder
+	outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec = &statusRecorder{ResponseWriter: w}
+		inner.ServeHTTP(rec, r)
+	})
+
+	srv := httptest.NewServer(outer)
+	t.Cleanup(srv.Close)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, body, gunzip(t, raw))
+	require.Equal(t, http.StatusAccepted, rec.status)
+	require.Equal(t, len(raw), rec.bytes, "outer middleware counts bytes on the wire")
+}
@@ -65,6 +65,40 @@ func TestNewMux_WithAndWithoutPprof(t *testing.T) {
 	require.Less(t, rr2.Code, 500)
 }
 
+func TestNewMux_GroupPrefixes(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path+" id="+r.PathValue("id"))
+	})
+	handlers := httpfx.Group("/v1",
+		httpfx.Handler{Pattern: "GET /users/{id}", Handler: echo},
+		httpfx.Handler{Pattern: "/static/", Handler: echo},
+	)
+	handlers = append(handlers, httpfx.Group("api/", httpfx.Group("/v2/",
+		httpfx.Handler{Pattern: "POST example.com/users/{id}", Handler: echo},
+	)...)...)
+	mux := httpfx.NewMux(httpfx.Params{Cfg: &httpfx.Config{}, Handlers: handlers})
+
+	cases := []struct {
+		method, url string
+		code        int
+		body        string
+	}{
+		{"GET", "/v1/users/7", http.StatusOK, "/v1/users/7 id=7"},
+		{"POST", "/v1/users/7", http.StatusMethodNotAllowed, ""},
+		{"GET", "/users/7", http.StatusNotFound, ""},
+		{"GET", "/v1/static/a.css", http.StatusOK, "/v1/static/a.css id="},
+		{"POST", "http://example.com/api/v2/users/9", http.StatusOK, "/api/v2/users/9 id=9"},
+	}
+	for _, c := range cases {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(c.method, c.url, nil))
+		require.Equal(t, c.code, rr.Code, "%s %s", c.method, c.url)
+		if c.body != "" {
+			require.Equal(t, c.body, rr.Body.String())
+		}
+	}
+}
+
 // --- Fx Module Lifecycle ---
 
 func TestModule_StartStopWithHandler(t *testing.T) {
@@ -0,0 +1,51 @@
+package httpkit
+
+import "net/http"
+
+// ReadyFuncName is the Fx name under which a readiness func can be provided
+// for Config.ReadinessGate:
+//
+//	fx.Provide(fx.Annotate(func(h *healthkit.Health) func() bool { return h.Ready },
+//	    fx.ResultTags(`name:"http.ready"`)))
+//
+// healthkit.HandlerModule provides it.
+const ReadyFuncName = "http.ready"
+
+// ReadinessExemptGroup is the Fx group through which modules add paths the
+// server's readiness gate always serves, on top of DefaultReadinessExempt and
+// Config.ReadinessExempt:
+//
+//	fx.Provide(fx.Annotate(func() []string { return []string{"/_status"} },
+//	    fx.ResultTags(`group:"http.readiness_exempt,flatten"`)))
+//
+// healthkit.HandlerModule adds its configured path.
+const ReadinessExemptGroup = "http.readiness_exempt"
+
+// DefaultReadinessExempt lists the paths ReadinessGate serves regardless of
+// readiness, so probes and metrics scrapes keep working while the service is
+// not ready.
+var DefaultReadinessExempt = []string{"/health", "/livez", "/readyz", "/metrics"}
+
+// ReadinessGate returns middleware that answers 503 with WriteError's JSON
+// body while ready reports false, so requests fail fast during startup and
+// drain instead of reaching handlers. Requests for the exempt paths (exact
+// match; DefaultReadinessExempt when none are given) always pass through.
+func ReadinessGate(ready func() bool, exempt ...string) func(http.Handler) http.Handler {
+	if len(exempt) == 0 {
+		exempt = DefaultReadinessExempt
+	}
+	skip := make(map[string]struct{}, len(exempt))
+	for _, p := range exempt {
+		skip[p] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := skip[r.URL.Path]; ok || ready() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Retry-After", "1")
+			WriteError(w, http.StatusServiceUnavailable, "service is not ready")
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package httpkit_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	httpfx "github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestReadinessGate(t *testing.T) {
+	var ready atomic.Bool
+	base := startServer(t, httpfx.Config{ReadinessGate: true},
+		fx.Provide(fx.Annotate(func() func() bool { return ready.Load }, fx.ResultTags(`name:"http.ready"`))),
+		fx.Provide(fx.Annotate(
+			func() httpfx.Handler {
+				return httpfx.Handler{Pattern: "/health", Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				})}
+			},
+			fx.ResultTags(`group:"http.handlers"`),
+		)),
+	)
+
+	resp, body := do(t, http.MethodGet, base+"/ping")
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get("Retry-After"))
+	require.JSONEq(t, `{"status":503,"error":"Service Unavailable","message":"service is not ready"}`, body)
+
+	// The probe path is never gated.
+	resp, _ = do(t, http.MethodGet, base+"/health")
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("Retry-After"))
+
+	ready.Store(true)
+	resp, body = do(t, http.MethodGet, base+"/ping")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "pong", body)
+}
+
+func TestReadinessGate_NoFuncIsNoop(t *testing.T) {
+	base := startServer(t, httpfx.Config{ReadinessGate: true})
+	resp, _ := do(t, http.MethodGet, base+"/ping")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestReadinessGate_ConfiguredExempt(t *testing.T) {
+	base := startServer(t, httpfx.Config{ReadinessGate: true, ReadinessExempt: []string{"/_status"}},
+		fx.Provide(fx.Annotate(func() func() bool { return func() bool { return false } }, fx.ResultTags(`name:"http.ready"`))),
+		fx.Provide(fx.Annotate(
+			func() httpfx.Handler {
+				return httpfx.Handler{Pattern: "/_status", Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})}
+			},
+			fx.ResultTags(`group:"http.handlers"`),
+		)),
+	)
+
+	resp, _ := do(t, http.MethodGet, base+"/_status")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = do(t, http.MethodGet, base+"/ping")
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
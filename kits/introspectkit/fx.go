@@ -0,0 +1,127 @@
+// Package introspectkit shows what an assembled Fx app provides: every
+// constructor, the types it outputs, and the dependency graph. It is a
+// debugging aid for "missing type" errors and for checking which kits are
+// wired in.
+package introspectkit
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+// Provided describes one constructor, or supplied value, and the types it
+// adds to the container.
+type Provided struct {
+	Constructor string // e.g. "github.com/froppa/stackkit/kits/telemetry.NewProviders()"
+	Module      string // Fx module name; empty for the root
+	Types       []string
+}
+
+// Recorder collects the Provided and Supplied events Fx emits while building
+// the app. Install it with Option and inspect it, or let Module log it.
+type Recorder struct {
+	next fxevent.Logger
+
+	mu       sync.Mutex
+	provided []Provided
+}
+
+// NewRecorder returns a recorder that forwards every event to next, or
+// discards them when next is nil.
+func NewRecorder(next fxevent.Logger) *Recorder {
+	if next == nil {
+		next = fxevent.NopLogger
+	}
+	return &Recorder{next: next}
+}
+
+// Option installs r as the app's Fx event logger and supplies it to the
+// container for Module. It replaces any other fx.WithLogger; pass that logger
+// to NewRecorder instead.
+func (r *Recorder) Option() fx.Option {
+	return fx.Options(
+		fx.WithLogger(func() fxevent.Logger { return r }),
+		fx.Supply(r),
+	)
+}
+
+// LogEvent implements fxevent.Logger.
+func (r *Recorder) LogEvent(e fxevent.Event) {
+	var p *Provided
+	switch e := e.(type) {
+	case *fxevent.Provided:
+		if e.Err == nil {
+			p = &Provided{Constructor: e.ConstructorName, Module: e.ModuleName, Types: e.OutputTypeNames}
+		}
+	case *fxevent.Supplied:
+		if e.Err == nil {
+			p = &Provided{Constructor: "fx.Supply", Module: e.ModuleName, Types: []string{e.TypeName}}
+		}
+	}
+	if p != nil {
+		r.mu.Lock()
+		r.provided = append(r.provided, *p)
+		r.mu.Unlock()
+	}
+	r.next.LogEvent(e)
+}
+
+// Provided returns the recorded constructors in the order Fx registered them.
+func (r *Recorder) Provided() []Provided {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Provided(nil), r.provided...)
+}
+
+// WriteTo prints one "type <- constructor" line per provided type, sorted by
+// type.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	var lines []string
+	for _, p := range r.Provided() {
+		for _, t := range p.Types {
+			lines = append(lines, t+" <- "+p.Constructor)
+		}
+	}
+	sort.Strings(lines)
+	n, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return int64(n), err
+}
+
+type deps struct {
+	fx.In
+	Logger   *zap.Logger
+	Graph    fx.DotGraph
+	Recorder *Recorder `optional:"true"`
+}
+
+// Module logs, at debug level, the app's dependency graph in DOT format
+// ("fx.graph") and, when a Recorder is installed, one "fx.provided" line per
+// constructor with the types it provides.
+//
+//	rec := introspectkit.NewRecorder(nil)
+//	fx.New(rec.Option(), introspectkit.Module(), logkit.Module(), ...)
+func Module() fx.Option {
+	return fx.Invoke(logGraph)
+}
+
+func logGraph(d deps) {
+	if !d.Logger.Core().Enabled(zap.DebugLevel) {
+		return
+	}
+	if d.Recorder != nil {
+		for _, p := range d.Recorder.Provided() {
+			d.Logger.Debug("fx.provided",
+				zap.String("constructor", p.Constructor),
+				zap.String("module", p.Module),
+				zap.Strings("types", p.Types),
+			)
+		}
+	}
+	d.Logger.Debug("fx.graph", zap.String("dot", string(d.Graph)))
+}
@@ -0,0 +1,51 @@
+package introspectkit_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/introspectkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type store struct{}
+
+func newStore() *store { return &store{} }
+
+func TestRecorderAndModule(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	rec := introspectkit.NewRecorder(nil)
+
+	app := fxtest.New(t,
+		rec.Option(),
+		introspectkit.Module(),
+		fx.Supply(zap.New(core)),
+		fx.Provide(newStore),
+		fx.Invoke(func(*store) {}),
+	)
+	app.RequireStart().RequireStop()
+
+	var found bool
+	for _, p := range rec.Provided() {
+		if p.Constructor == "github.com/froppa/stackkit/kits/introspectkit_test.newStore()" {
+			require.Equal(t, []string{"*introspectkit_test.store"}, p.Types)
+			found = true
+		}
+	}
+	require.True(t, found, "newStore should be recorded")
+
+	var buf bytes.Buffer
+	_, err := rec.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "*introspectkit_test.store <- github.com/froppa/stackkit/kits/introspectkit_test.newStore()\n")
+
+	require.NotZero(t, logs.FilterMessage("fx.provided").Len())
+	graph := logs.FilterMessage("fx.graph").All()
+	require.Len(t, graph, 1)
+	require.Contains(t, graph[0].ContextMap()["dot"], "digraph")
+}
@@ -0,0 +1,39 @@
+package logkit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+var base atomic.Pointer[zap.Logger]
+
+func init() { base.Store(zap.NewNop()) }
+
+// SetBase sets the logger FromContext returns for contexts that carry none.
+// Module calls it with the logger it provides; it defaults to a no-op logger.
+func SetBase(log *zap.Logger) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	base.Store(log)
+}
+
+// ContextWith returns a copy of ctx whose logger carries fields in addition
+// to those already added to ctx, so fields accumulate as a request passes
+// through handlers (request ID, then user, then tenant, ...).
+func ContextWith(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, ctxKey{}, FromContext(ctx).With(fields...))
+}
+
+// FromContext returns the logger stored in ctx by ContextWith, or the base
+// logger (see SetBase) when there is none. It never returns nil.
+func FromContext(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok {
+		return log
+	}
+	return base.Load()
+}
@@ -0,0 +1,35 @@
+package logkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/logkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestContextWith_AccumulatesFields(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logkit.SetBase(zap.New(core))
+	t.Cleanup(func() { logkit.SetBase(nil) })
+
+	// Without fields in ctx, the base logger is used.
+	logkit.FromContext(context.Background()).Info("plain")
+
+	ctx := logkit.ContextWith(context.Background(), zap.String("request_id", "r1"))
+	ctx = logkit.ContextWith(ctx, zap.String("tenant", "acme"))
+	logkit.FromContext(ctx).Info("handled")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	require.Empty(t, entries[0].Context)
+	require.Equal(t, map[string]any{"request_id": "r1", "tenant": "acme"}, entries[1].ContextMap())
+}
+
+func TestFromContext_NeverNil(t *testing.T) {
+	logkit.SetBase(nil)
+	require.NotNil(t, logkit.FromContext(context.Background()))
+}
@@ -0,0 +1,47 @@
+package logkit
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// CoreGroup is the fx value group Module collects extra zapcore.Cores from.
+// Every core receives the same entries, and runtimeinfo fields, as the
+// primary output, filtered by the configured level. Only the primary output
+// is sampled, so an extra core sees every enabled entry:
+//
+//	fx.Provide(fx.Annotate(newRingCore, fx.ResultTags(`group:"`+logkit.CoreGroup+`"`)))
+const CoreGroup = "logkit.cores"
+
+// teeCores combines the primary core with extra cores, each gated by level so
+// a core built with a lower threshold cannot bypass the configured level.
+func teeCores(primary zapcore.Core, level zapcore.LevelEnabler, extra []zapcore.Core) zapcore.Core {
+	cores := make([]zapcore.Core, 0, len(extra)+1)
+	cores = append(cores, primary)
+	for _, c := range extra {
+		if c != nil {
+			cores = append(cores, levelCore{Core: c, level: level})
+		}
+	}
+	return zapcore.NewTee(cores...)
+}
+
+// levelCore restricts a core to the entries level enables.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l) && c.Core.Enabled(l)
+}
+
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c levelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(e.Level) {
+		return ce
+	}
+	return c.Core.Check(e, ce)
+}
@@ -0,0 +1,65 @@
+package logkit_test
+
+import (
+	"testing"
+
+	"github.com/froppa/stackkit/kits/logkit"
+	info "github.com/froppa/stackkit/kits/runtimeinfo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestModule_TeesContributedCores(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	var log *zap.Logger
+	app := fxtest.New(t,
+		logkit.Module(), // level info
+		fx.Provide(fx.Annotate(
+			func() zapcore.Core { return core },
+			fx.ResultTags(`group:"`+logkit.CoreGroup+`"`),
+		)),
+		fx.Populate(&log),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	log.Debug("hidden")
+	log.Info("shown")
+
+	var msgs []string
+	for _, e := range logs.AllUntimed() {
+		msgs = append(msgs, e.Message)
+		if e.Message == "shown" {
+			require.Equal(t, info.Name, e.ContextMap()["name"])
+		}
+	}
+	require.Contains(t, msgs, "shown")
+	require.NotContains(t, msgs, "hidden", "debug must be filtered by the configured level")
+}
+
+func TestNew_ExtraCoresFollowLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log, err := logkit.New(logkit.Config{Encoding: "production", Level: "warn"}, core)
+	require.NoError(t, err)
+
+	log.Info("dropped")
+	log.Warn("kept")
+	require.Equal(t, 1, logs.Len())
+	require.Equal(t, "kept", logs.All()[0].Message)
+}
+
+func TestNew_ExtraCoresAreNotSampled(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log, err := logkit.New(logkit.Config{Encoding: "production", Level: "info"}, core)
+	require.NoError(t, err)
+
+	// The production sampler keeps the first 100 identical entries per second.
+	for range 150 {
+		log.Info("repeated")
+	}
+	require.Equal(t, 150, logs.Len())
+}
@@ -9,32 +9,44 @@ package logkit
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/froppa/stackkit/kits/configkit"
 	"github.com/froppa/stackkit/kits/runtimeinfo"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+func init() { configkit.RegisterKnown(configKey, (*Config)(nil)) }
+
+// configKey is the config subtree Module reads.
+const configKey = "log"
+
+// loadedConfigName names the Config Module reads from configkit. It stays
+// out of the unnamed slot so applications can still fx.Provide their own.
+const loadedConfigName = "logkit.loaded"
+
 // Module provides a configured *zap.Logger and *zap.SugaredLogger to the Fx
-// application container.
+// application container, along with the zap.AtomicLevel controlling it. The
+// Config is read from the "log" key of configkit's provider; missing values,
+// or a missing provider, fall back to production encoding at info level. A
+// *logkit.Config supplied with fx.Provide, fx.Supply or fx.Replace takes
+// precedence over the loaded one.
 func Module() fx.Option {
 	return fx.Options(
-		// Provide a default config. Users can override this in their application
-		// by providing their own logger.Config.
-		fx.Provide(func() Config {
-			return Config{
-				// "production" is a safe default for structured, efficient logging.
-				Encoding: "production",
-				Level:    "info",
-			}
-		}),
-		fx.Provide(New),
+		fx.Provide(fx.Annotate(
+			provideConfig(configkit.ProvideFromKey[Config](configKey)),
+			fx.ResultTags(`name:"`+loadedConfigName+`"`),
+		)),
+		fx.Provide(provide),
 		fx.Provide(func(log *zap.Logger) *zap.SugaredLogger {
 			return log.Sugar()
 		}),
 		fx.Invoke(RegisterHooks),
+		fx.Invoke(SetBase),
 	)
 }
 
@@ -42,15 +54,69 @@ func Module() fx.Option {
 type Config struct {
 	// Encoding sets the logger's output format. Use "production|json" for JSON
 	// or "development" for a human-readable console format.
-	Encoding string `yaml:"encoding" validate:"required,oneof=production prod json development dev console"`
+	Encoding string `yaml:"encoding" default:"production" validate:"required,ci,oneof=production prod json development dev console"`
 
 	// Level is the minimum log level to record, e.g., "debug", "info", "warn".
-	Level string `yaml:"level" validate:"required,oneof=debug info warn error dpanic panic fatal"`
+	Level string `yaml:"level" default:"info" validate:"required,ci,oneof=debug info warn error dpanic panic fatal"`
+
+	// SplitStreams writes warn and above to stderr and everything below to
+	// stdout. By default all entries go to stderr.
+	SplitStreams bool `yaml:"split_streams"`
 }
 
 // New constructs a new *zap.Logger based on the provided configuration.
 // It enriches the logger with application metadata from the runtimeinfo package.
-func New(cfg Config) (*zap.Logger, error) {
+// Entries are also written to every extra core, filtered by cfg.Level.
+func New(cfg Config, cores ...zapcore.Core) (*zap.Logger, error) {
+	log, _, err := build(cfg, cores...)
+	return log, err
+}
+
+type configParams struct {
+	fx.In
+	Provider configkit.Provider `optional:"true"`
+}
+
+// provideConfig wraps load, which reads and validates the "log" subtree, to
+// return the defaults when the application runs without configkit.
+func provideConfig(load func(configkit.Provider) (*Config, error)) func(configParams) (*Config, error) {
+	return func(p configParams) (*Config, error) {
+		if p.Provider == nil {
+			return &Config{Encoding: "production", Level: "info"}, nil
+		}
+		return load(p.Provider)
+	}
+}
+
+type params struct {
+	fx.In
+	Config *Config `optional:"true"`
+	// Struct tags cannot reference constants; keep this in sync with
+	// loadedConfigName.
+	Loaded *Config `name:"logkit.loaded"`
+	// Struct tags cannot reference constants; keep this in sync with CoreGroup.
+	Cores []zapcore.Core `group:"logkit.cores"`
+}
+
+type result struct {
+	fx.Out
+	Logger *zap.Logger
+	Level  zap.AtomicLevel
+}
+
+func provide(p params) (result, error) {
+	// An application-provided Config wins over the loaded one.
+	cfg := p.Loaded
+	if p.Config != nil {
+		cfg = p.Config
+	}
+	log, level, err := build(*cfg, p.Cores...)
+	return result{Logger: log, Level: level}, err
+}
+
+// build constructs the logger and returns the atomic level it filters on, so
+// the level can be changed at runtime.
+func build(cfg Config, cores ...zapcore.Core) (*zap.Logger, zap.AtomicLevel, error) {
 	var zapCfg zap.Config
 	switch strings.ToLower(cfg.Encoding) {
 	case "prod", "production", "json":
@@ -60,24 +126,75 @@ func New(cfg Config) (*zap.Logger, error) {
 		// Use a more readable time format for development.
 		zapCfg.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 	default:
-		return nil, fmt.Errorf("unknown logger encoding: %q", cfg.Encoding)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("unknown logger encoding: %q", cfg.Encoding)
 	}
 
 	// Parse and set the log level.
-	level, err := zapcore.ParseLevel(cfg.Level)
+	level, err := parseLevel(cfg.Level)
 	if err != nil {
-		return nil, fmt.Errorf("invalid log level: %w", err)
+		return nil, zap.AtomicLevel{}, err
 	}
 	zapCfg.Level = zap.NewAtomicLevelAt(level)
 
+	var opts []zap.Option
+	if cfg.SplitStreams {
+		opts = append(opts, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return splitCore(zapCfg, zapcore.Lock(os.Stdout), zapcore.Lock(os.Stderr))
+		}))
+	}
+
+	if len(cores) > 0 {
+		opts = append(opts, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return teeCores(c, zapCfg.Level, cores)
+		}))
+	}
+
 	// Build the logger.
-	logger, err := zapCfg.Build()
+	logger, err := zapCfg.Build(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build zap logger: %w", err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to build zap logger: %w", err)
 	}
 
 	// Add permanent fields from the runtimeinfo package.
-	return logger.With(runtimeinfo.Fields()...), nil
+	return logger.With(runtimeinfo.Fields()...), zapCfg.Level, nil
+}
+
+// parseLevel parses a level the way Config validates it, ignoring case.
+func parseLevel(s string) (zapcore.Level, error) {
+	level, err := zapcore.ParseLevel(strings.ToLower(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid log level: %w", err)
+	}
+	return level, nil
+}
+
+// splitCore replaces the single output core built by zap.Config with a tee of
+// two level-filtered cores: entries below warn go to out, the rest to errOut.
+func splitCore(zapCfg zap.Config, out, errOut zapcore.WriteSyncer) zapcore.Core {
+	var enc zapcore.Encoder
+	if zapCfg.Encoding == "console" {
+		enc = zapcore.NewConsoleEncoder(zapCfg.EncoderConfig)
+	} else {
+		enc = zapcore.NewJSONEncoder(zapCfg.EncoderConfig)
+	}
+
+	level := zapCfg.Level
+	low := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return level.Enabled(l) && l < zapcore.WarnLevel
+	})
+	high := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return level.Enabled(l) && l >= zapcore.WarnLevel
+	})
+	core := zapcore.NewTee(
+		zapcore.NewCore(enc, out, low),
+		zapcore.NewCore(enc.Clone(), errOut, high),
+	)
+
+	// WrapCore discards the sampler zap.Config installed; restore it.
+	if s := zapCfg.Sampling; s != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, s.Initial, s.Thereafter)
+	}
+	return core
 }
 
 // registerHooks attaches OnStart and OnStop hooks to the application lifecycle.
@@ -6,9 +6,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/froppa/stackkit/kits/configkit"
 	"github.com/froppa/stackkit/kits/logkit"
 	info "github.com/froppa/stackkit/kits/runtimeinfo"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -107,3 +109,37 @@ func TestModuleIntegration(t *testing.T) {
 		t.Fatalf("app stop failed: %v", err)
 	}
 }
+
+func TestModule_ReadsConfigFromProvider(t *testing.T) {
+	level := func(t *testing.T, opts ...fx.Option) zapcore.Level {
+		t.Helper()
+		var lvl zap.AtomicLevel
+		app := fxtest.New(t, append(opts, logkit.Module(), fx.Populate(&lvl))...)
+		app.RequireStart()
+		app.RequireStop()
+		return lvl.Level()
+	}
+
+	yaml := func(s string) fx.Option {
+		return configkit.Module(configkit.WithEmbeddedBytes([]byte(s)))
+	}
+	if got := level(t, yaml("log:\n  level: debug\n")); got != zapcore.DebugLevel {
+		t.Fatalf("expected debug from YAML, got %s", got)
+	}
+	if got := level(t, yaml("other: true\n")); got != zapcore.InfoLevel {
+		t.Fatalf("expected info default without a log key, got %s", got)
+	}
+	if got := level(t, yaml("log:\n  level: debug\n"), fx.Provide(func() *logkit.Config {
+		return &logkit.Config{Encoding: "json", Level: "error"}
+	})); got != zapcore.ErrorLevel {
+		t.Fatalf("expected the provided level, got %s", got)
+	}
+	if got := level(t, fx.Replace(&logkit.Config{Encoding: "json", Level: "warn"})); got != zapcore.WarnLevel {
+		t.Fatalf("expected the replaced level, got %s", got)
+	}
+
+	app := fx.New(fx.NopLogger, yaml("log:\n  level: verbose\n"), logkit.Module(), fx.Invoke(func(*zap.Logger) {}))
+	if err := app.Err(); err == nil || !strings.Contains(err.Error(), "log.level") {
+		t.Fatalf("expected a validation error for log.level, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package logkit
+
+import (
+	"context"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	uber "go.uber.org/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ReloadLevel watches the config files behind configkit.Module and, whenever
+// they change, applies the new `log.level` to the logger's zap.AtomicLevel
+// without rebuilding it. Pass the same options given to configkit.Module so
+// the reloaded config is layered identically. It requires Module and
+// configkit.Module.
+//
+// When configkit.Module runs with configkit.WithWatch, ReloadLevel follows its
+// *configkit.ReloadNotifier instead of watching on its own, so each edit is
+// loaded, and counted by configkit.RecordReload, once. Otherwise it counts its
+// own reloads.
+//
+// The "log" subtree is read and validated as Module reads it, so a removed
+// `log.level` falls back to info; an invalid value or a config that fails to
+// load is logged and ignored.
+func ReloadLevel(opts ...configkit.ModuleOption) fx.Option {
+	load := configkit.ProvideFromKey[Config](configKey)
+	return fx.Invoke(func(p reloadParams) {
+		r := levelReloader{opts: opts, load: load, level: p.Level, log: p.Log}
+		if p.Notifier != nil {
+			var unsubscribe func()
+			p.LC.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					unsubscribe = p.Notifier.Subscribe(r.apply)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					unsubscribe()
+					return nil
+				},
+			})
+			return
+		}
+		files := configkit.Files(p.Provider)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		p.LC.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go func() {
+					defer close(done)
+					_ = configkit.WatchFiles(ctx, files, configkit.WatchOptions{}, r.reload)
+				}()
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				<-done
+				return nil
+			},
+		})
+	})
+}
+
+type reloadParams struct {
+	fx.In
+	LC       fx.Lifecycle
+	Provider *uber.YAML
+	Level    zap.AtomicLevel
+	Log      *zap.Logger
+	Notifier *configkit.ReloadNotifier `optional:"true"`
+}
+
+type levelReloader struct {
+	opts  []configkit.ModuleOption
+	load  func(configkit.Provider) (*Config, error)
+	level zap.AtomicLevel
+	log   *zap.Logger
+}
+
+func (r levelReloader) reload() {
+	p, err := configkit.Load(r.opts...)
+	configkit.RecordReload(err)
+	if err != nil {
+		r.log.Warn("log level reload failed; keeping current level", zap.Error(err))
+		return
+	}
+	r.apply(p)
+}
+
+// apply sets the level read from p.
+func (r levelReloader) apply(p *uber.YAML) {
+	next, err := r.read(p)
+	if err != nil {
+		r.log.Warn("log level reload failed; keeping current level", zap.Error(err))
+		return
+	}
+	if next == r.level.Level() {
+		return
+	}
+	prev := r.level.Level()
+	r.level.SetLevel(next)
+	r.log.Info("log level changed", zap.Stringer("from", prev), zap.Stringer("to", next))
+}
+
+func (r levelReloader) read(p *uber.YAML) (zapcore.Level, error) {
+	cfg, err := r.load(p)
+	if err != nil {
+		return 0, err
+	}
+	return parseLevel(cfg.Level)
+}
@@ -0,0 +1,95 @@
+package logkit_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/logkit"
+	"github.com/stretchr/testify/require"
+	uber "go.uber.org/config"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestReloadLevel_AppliesNewLevelFromConfig(t *testing.T) {
+	tmp := t.TempDir()
+	cwd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmp))
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	path := filepath.Join("config", "config.yml")
+	require.NoError(t, os.MkdirAll("config", 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: info\n"), 0o644))
+
+	var level zap.AtomicLevel
+	core, logs := observer.New(zapcore.WarnLevel)
+	app := fxtest.New(t,
+		configkit.Module(),
+		logkit.Module(),
+		logkit.ReloadLevel(),
+		fx.Provide(fx.Annotate(func() zapcore.Core { return core }, fx.ResultTags(`group:"`+logkit.CoreGroup+`"`))),
+		fx.Populate(&level),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+	require.Equal(t, zapcore.InfoLevel, level.Level())
+
+	// Rewrite until seen, as the watcher starts in the background; the
+	// interval leaves room for the watcher's debounce.
+	require.Eventually(t, func() bool {
+		require.NoError(t, os.WriteFile(path, []byte("log:\n  level: debug\n"), 0o644))
+		return level.Level() == zapcore.DebugLevel
+	}, 5*time.Second, 500*time.Millisecond)
+
+	// Invalid values are ignored rather than applied.
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: verbose\n"), 0o644))
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("log level reload failed; keeping current level").Len() > 0
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
+func TestReloadLevel_FollowsReloadNotifier(t *testing.T) {
+	configkit.ResetReloadsForTests()
+	t.Cleanup(configkit.ResetReloadsForTests)
+
+	var (
+		level    zap.AtomicLevel
+		notifier *configkit.ReloadNotifier
+	)
+	app := fxtest.New(t,
+		configkit.Module(configkit.WithSources(uber.Source(bytes.NewBufferString("log:\n  level: info\n")))),
+		fx.Provide(configkit.NewReloadNotifier),
+		logkit.Module(),
+		logkit.ReloadLevel(),
+		fx.Populate(&level, &notifier),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	next, err := uber.NewYAML(uber.Source(bytes.NewBufferString("log:\n  level: debug\n")))
+	require.NoError(t, err)
+	notifier.Publish(next)
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+
+	// Values are validated and parsed as at startup, ignoring case.
+	next, err = uber.NewYAML(uber.Source(bytes.NewBufferString("log:\n  level: Warn\n")))
+	require.NoError(t, err)
+	notifier.Publish(next)
+	require.Equal(t, zapcore.WarnLevel, level.Level())
+
+	// A removed level falls back to the default.
+	next, err = uber.NewYAML(uber.Source(bytes.NewBufferString("other: true\n")))
+	require.NoError(t, err)
+	notifier.Publish(next)
+	require.Equal(t, zapcore.InfoLevel, level.Level())
+	// The notifier's owner counts reloads; ReloadLevel does not count again.
+	require.Zero(t, configkit.Reloads().Total)
+}
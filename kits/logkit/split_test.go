@@ -0,0 +1,61 @@
+package logkit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSplitCore_RoutesByLevel(t *testing.T) {
+	var out, errOut bytes.Buffer
+	zapCfg := zap.NewProductionConfig()
+	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	zapCfg.Sampling = nil
+
+	log := zap.New(splitCore(zapCfg, zapcore.AddSync(&out), zapcore.AddSync(&errOut)))
+	log.Debug("debug-msg")
+	log.Info("info-msg")
+	log.Warn("warn-msg")
+	log.Error("error-msg")
+
+	for _, msg := range []string{"debug-msg", "info-msg"} {
+		if !strings.Contains(out.String(), msg) || strings.Contains(errOut.String(), msg) {
+			t.Errorf("%s should go to stdout only; out=%q err=%q", msg, out.String(), errOut.String())
+		}
+	}
+	for _, msg := range []string{"warn-msg", "error-msg"} {
+		if !strings.Contains(errOut.String(), msg) || strings.Contains(out.String(), msg) {
+			t.Errorf("%s should go to stderr only; out=%q err=%q", msg, out.String(), errOut.String())
+		}
+	}
+}
+
+func TestSplitCore_HonorsLevel(t *testing.T) {
+	var out, errOut bytes.Buffer
+	zapCfg := zap.NewDevelopmentConfig()
+	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.WarnLevel)
+
+	log := zap.New(splitCore(zapCfg, zapcore.AddSync(&out), zapcore.AddSync(&errOut)))
+	log.Info("dropped")
+	log.Warn("kept")
+
+	if out.Len() != 0 {
+		t.Errorf("expected nothing on stdout, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "kept") {
+		t.Errorf("expected warn on stderr, got %q", errOut.String())
+	}
+}
+
+func TestNew_SplitStreams(t *testing.T) {
+	log, err := New(Config{Encoding: "json", Level: "info", SplitStreams: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !log.Core().Enabled(zapcore.InfoLevel) || log.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatalf("split logger should honor configured level")
+	}
+}
@@ -0,0 +1,50 @@
+package logkit
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithContext returns log with trace_id and span_id fields for the span
+// active in ctx, so log lines can be correlated with traces. log is returned
+// unchanged when ctx carries no valid span context.
+func WithContext(ctx context.Context, log *zap.Logger) *zap.Logger {
+	fields := traceFields(ctx)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.With(fields...)
+}
+
+// Context returns a field that adds the trace_id and span_id of the span
+// active in ctx, and nothing when there is none. It works with any zap
+// logger and core, per call or through With:
+//
+//	log.Info("charged", logkit.Context(ctx), zap.Int("cents", n))
+func Context(ctx context.Context) zap.Field {
+	return zap.Inline(ctxFields{ctx})
+}
+
+// ctxFields marshals the trace fields of a context inline.
+type ctxFields struct{ ctx context.Context }
+
+func (c ctxFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range traceFields(c.ctx) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+func traceFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	}
+}
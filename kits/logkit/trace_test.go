@@ -0,0 +1,55 @@
+package logkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/logkit"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithContext_AddsTraceFields(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := zap.New(core)
+
+	// No span: the logger is returned as is.
+	require.Same(t, log, logkit.WithContext(context.Background(), log))
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	logkit.WithContext(ctx, log).Info("traced")
+
+	sc := span.SpanContext()
+	require.Equal(t, map[string]any{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}, logs.All()[0].ContextMap())
+}
+
+func TestContextField_AddsTraceFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log, err := logkit.New(logkit.Config{Encoding: "production", Level: "info"}, core)
+	require.NoError(t, err)
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	log.Info("per call", logkit.Context(ctx), zap.Int("n", 1))
+	log.With(logkit.Context(ctx)).Warn("via with")
+	log.Info("no span", logkit.Context(context.Background()))
+	log.Debug("filtered", logkit.Context(ctx))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 3)
+	traceID := span.SpanContext().TraceID().String()
+	for _, e := range entries[:2] {
+		fields := e.ContextMap()
+		require.Equal(t, traceID, fields["trace_id"], e.Message)
+		require.Equal(t, span.SpanContext().SpanID().String(), fields["span_id"], e.Message)
+	}
+	require.EqualValues(t, 1, entries[0].ContextMap()["n"])
+	require.NotContains(t, entries[2].ContextMap(), "trace_id")
+}
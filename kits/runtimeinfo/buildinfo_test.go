@@ -0,0 +1,43 @@
+package runtimeinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVCS(t *testing.T) {
+	origCommit, origDate, origDirty := Commit, Date, Dirty
+	t.Cleanup(func() { Commit, Date, Dirty = origCommit, origDate, origDirty })
+
+	settings := []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "0123abcd"},
+		{Key: "vcs.time", Value: "2025-10-03T19:15:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+
+	t.Run("fills unset values", func(t *testing.T) {
+		Commit, Date, Dirty = "", "", false
+		applyVCS(settings)
+		require.Equal(t, "0123abcd", Commit)
+		require.Equal(t, "2025-10-03T19:15:00Z", Date)
+		require.True(t, Dirty)
+		require.True(t, GetMetadata().Dirty)
+	})
+
+	t.Run("ldflags win", func(t *testing.T) {
+		Commit, Date, Dirty = "from-ldflags", "yesterday", false
+		applyVCS(settings)
+		require.Equal(t, "from-ldflags", Commit)
+		require.Equal(t, "yesterday", Date)
+		require.True(t, Dirty)
+	})
+
+	t.Run("clean build", func(t *testing.T) {
+		Dirty = true
+		applyVCS([]debug.BuildSetting{{Key: "vcs.modified", Value: "false"}})
+		require.False(t, Dirty)
+	})
+}
@@ -2,6 +2,8 @@
 //
 // It offers a standard way to embed version, commit, and other build information
 // into a Go binary and expose it in common formats for observability.
+// Commit, Date and Dirty fall back to the VCS info stamped by the Go toolchain
+// when not injected.
 //
 // # Example usage in build scripts
 //
@@ -16,6 +18,8 @@
 package runtimeinfo
 
 import (
+	"runtime/debug"
+
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.uber.org/zap"
@@ -44,8 +48,38 @@ var (
 
 	// GoVersion is the Go toolchain version used to compile the binary.
 	GoVersion string
+
+	// Dirty reports whether the binary was built from a working tree with
+	// uncommitted changes. Read from the Go build info (vcs.modified).
+	Dirty bool
 )
 
+func init() {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		applyVCS(bi.Settings)
+	}
+}
+
+// applyVCS fills metadata from the VCS settings the Go toolchain stamps into
+// the binary. Values injected via -ldflags always win; Version is never taken
+// from build info, so a "+dirty" module version does not leak into it.
+func applyVCS(settings []debug.BuildSetting) {
+	for _, s := range settings {
+		switch s.Key {
+		case "vcs.revision":
+			if Commit == "" {
+				Commit = s.Value
+			}
+		case "vcs.time":
+			if Date == "" {
+				Date = s.Value
+			}
+		case "vcs.modified":
+			Dirty = s.Value == "true"
+		}
+	}
+}
+
 // Meta contains the full build metadata for introspection or logging.
 type Meta struct {
 	Name        string `json:"name"`
@@ -55,6 +89,7 @@ type Meta struct {
 	Date        string `json:"build_time"`
 	BuiltBy     string `json:"built_by"`
 	GoVersion   string `json:"go_version"`
+	Dirty       bool   `json:"vcs_dirty"`
 }
 
 // GetMetadata returns a snapshot of the current build metadata.
@@ -67,6 +102,7 @@ func GetMetadata() Meta {
 		Date:        Date,
 		BuiltBy:     BuiltBy,
 		GoVersion:   GoVersion,
+		Dirty:       Dirty,
 	}
 }
 
@@ -81,6 +117,7 @@ func Fields() []zapcore.Field {
 		zap.String("build_date", Date),
 		zap.String("built_by", BuiltBy),
 		zap.String("go_version", GoVersion),
+		zap.Bool("vcs.dirty", Dirty),
 	}
 }
 
@@ -90,7 +127,7 @@ func Fields() []zapcore.Field {
 func OTELAttributes() []attribute.KeyValue {
 	m := GetMetadata()
 	// Conditionally add attributes to avoid empty strings for unset optional fields.
-	attrs := make([]attribute.KeyValue, 0, 7)
+	attrs := make([]attribute.KeyValue, 0, 8)
 
 	if m.Name != "" {
 		attrs = append(attrs, semconv.ServiceNameKey.String(m.Name))
@@ -116,6 +153,8 @@ func OTELAttributes() []attribute.KeyValue {
 	if m.BuiltBy != "" {
 		attrs = append(attrs, attribute.String("build.user", m.BuiltBy))
 	}
+	// Always reported: a dirty production build is worth flagging.
+	attrs = append(attrs, attribute.Bool("build.dirty", m.Dirty))
 	return attrs
 }
 
@@ -80,6 +80,14 @@ func TestMetadata(t *testing.T) {
 		require.Equal(t, "2025-10-03T19:15:00Z", fieldMap["build_date"])
 		require.Equal(t, "test-runner", fieldMap["built_by"])
 		require.Equal(t, "go1.99.9", fieldMap["go_version"])
+
+		var dirty bool
+		for _, f := range fields {
+			if f.Key == "vcs.dirty" {
+				dirty = f.Integer == 1
+			}
+		}
+		require.Equal(t, info.Dirty, dirty)
 	})
 
 	t.Run("OTELAttributes with all values set", func(t *testing.T) {
@@ -96,6 +104,7 @@ func TestMetadata(t *testing.T) {
 		require.Equal(t, "abcdef123", attrMap["vcs.revision"])
 		require.Equal(t, "2025-10-03T19:15:00Z", attrMap["build.time"])
 		require.Equal(t, "test-runner", attrMap["build.user"])
+		require.Contains(t, attrs, attribute.Bool("build.dirty", info.Dirty))
 	})
 
 	t.Run("OTELAttributes with optional values empty", func(t *testing.T) {
@@ -0,0 +1,28 @@
+package runtimeinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate reports build metadata a release build should have injected: an
+// empty Name (which also disables the service-specific config file) or a
+// Version left at "dev". It returns nil for development environments (dev,
+// development, local, test, or empty), where unset metadata is expected.
+func Validate(env string) error {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "", "dev", "development", "local", "test":
+		return nil
+	}
+	var problems []string
+	if strings.TrimSpace(Name) == "" {
+		problems = append(problems, "runtimeinfo.Name is empty")
+	}
+	if Version == "" || Version == "dev" {
+		problems = append(problems, fmt.Sprintf("runtimeinfo.Version is %q", Version))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("build metadata not set for environment %q (inject it via -ldflags): %s", env, strings.Join(problems, "; "))
+}
@@ -0,0 +1,23 @@
+package runtimeinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	origName, origVersion := Name, Version
+	t.Cleanup(func() { Name, Version = origName, origVersion })
+
+	Name, Version = "", "dev"
+	require.NoError(t, Validate("dev"))
+	require.NoError(t, Validate("Local"))
+
+	err := Validate("prod")
+	require.ErrorContains(t, err, "runtimeinfo.Name is empty")
+	require.ErrorContains(t, err, `runtimeinfo.Version is "dev"`)
+
+	Name, Version = "api", "1.2.3"
+	require.NoError(t, Validate("prod"))
+}
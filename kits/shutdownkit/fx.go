@@ -5,6 +5,7 @@ package shutdownkit
 
 import (
 	"context"
+	"runtime"
 	"sync"
 	"time"
 
@@ -17,7 +18,10 @@ import (
 type Option func(*opts)
 
 type opts struct {
-	timeout time.Duration
+	timeout    time.Duration
+	latch      bool
+	dumpStacks bool
+	preStop    time.Duration
 }
 
 // WithTimeout overrides the graceful wait bound during shutdown.
@@ -26,6 +30,37 @@ func WithTimeout(d time.Duration) Option {
 	return func(o *opts) { o.timeout = d }
 }
 
+// WithSignalLatch controls whether SIGINT/SIGTERM received while the app is
+// still starting is latched and turned into a shutdown once startup completes.
+// Enabled by default; without it, an early signal kills the process with
+// whatever state the OnStart hooks had reached.
+//
+// The latch is armed before the app's invokes run and handed off once the
+// OnStart hooks registered ahead of Module's have run, so list Module after
+// the modules whose startup it should cover. A failed start disarms it.
+func WithSignalLatch(enabled bool) Option {
+	return func(o *opts) { o.latch = enabled }
+}
+
+// WithStackDumpOnForce logs the stacks of all goroutines, once, when shutdown
+// escalates to force because the graceful wait timed out. The dump can be
+// large, so it is off by default.
+func WithStackDumpOnForce(enabled bool) Option {
+	return func(o *opts) { o.dumpStacks = enabled }
+}
+
+// WithPreStopDelay waits d after Fx stop begins and before graceful shutdown
+// is triggered, while the app keeps serving. It is the in-process version of a
+// Kubernetes preStop sleep: load balancers and DNS get time to drop the
+// instance before connections are refused. The wait ends early if the Fx stop
+// deadline expires. Zero (the default) disables it.
+//
+// Fx stops hooks in reverse registration order, so list Module after the
+// modules (e.g. httpkit) that must keep serving during the delay.
+func WithPreStopDelay(d time.Duration) Option {
+	return func(o *opts) { o.preStop = d }
+}
+
 // ctxOut exports contexts only. We avoid re-providing Shutdown/WG to prevent duplicates.
 type ctxOut struct {
 	fx.Out
@@ -38,15 +73,20 @@ type ctxOut struct {
 //   - context.Context `name:"graceful"`
 //   - context.Context `name:"force"`
 //   - *sync.WaitGroup
+//   - *Workers
 func Module(opt ...Option) fx.Option {
-	cfg := opts{timeout: 10 * time.Second}
+	cfg := opts{timeout: 10 * time.Second, latch: true}
 	for _, o := range opt {
 		o(&cfg)
 	}
+	var latch *signals.Latch
 	return fx.Options(
 		// Single shared WaitGroup
 		fx.Provide(func() *sync.WaitGroup { return &sync.WaitGroup{} }),
 
+		// Worker helper bound to the shared WaitGroup and logger
+		fx.Provide(NewWorkers),
+
 		// Single Shutdown coordinator (no OS signal handling here; Fx.Run owns signals)
 		fx.Provide(signals.New),
 
@@ -61,7 +101,37 @@ func Module(opt ...Option) fx.Option {
 		// On stop: trigger graceful, then bounded wait; escalate to force after timeout
 		fx.Invoke(func(lc fx.Lifecycle, log *zap.Logger, s *signals.Shutdown) {
 			lc.Append(fx.Hook{
-				OnStop: func(context.Context) error {
+				OnStop: func(ctx context.Context) error {
+					if cfg.preStop > 0 {
+						log.Info("shutdown: pre-stop delay", zap.Duration("delay", cfg.preStop))
+						t := time.NewTimer(cfg.preStop)
+						select {
+						case <-t.C:
+						case <-ctx.Done():
+							t.Stop()
+							log.Warn("shutdown: pre-stop delay cut short by stop deadline")
+						}
+					}
+					if cfg.dumpStacks {
+						// Dump while the stuck workers are still running, and
+						// finish the dump before the hook returns.
+						waited, dumped := make(chan struct{}), make(chan struct{})
+						defer func() {
+							close(waited)
+							<-dumped
+						}()
+						go func() {
+							defer close(dumped)
+							select {
+							case <-s.Force().Done():
+							case <-waited:
+								if s.Force().Err() == nil {
+									return
+								}
+							}
+							log.Warn("shutdown: forced, dumping goroutines", zap.String("stacks", goroutineStacks()))
+						}()
+					}
 					log.Info("shutdown: initiating graceful")
 					s.TriggerGraceful()
 					s.Wait(cfg.timeout)
@@ -70,9 +140,59 @@ func Module(opt ...Option) fx.Option {
 				},
 			})
 		}),
+
+		// Fx only listens for signals once Start returns. Latch signals that
+		// arrive earlier; the child module's invoke runs before the app's, so
+		// its hook comes first and is stopped even when startup fails.
+		fx.Module("shutdown/latch",
+			fx.Invoke(func(lc fx.Lifecycle) {
+				if !cfg.latch {
+					return
+				}
+				latch = signals.NewLatch()
+				lc.Append(fx.Hook{
+					OnStop: func(context.Context) error {
+						latch.Stop()
+						return nil
+					},
+				})
+			}),
+		),
+
+		// Once the hooks registered before this one have started, disarm the
+		// latch and hand a latched signal to the Shutdowner, which retains it
+		// until the app waits, so shutdown runs right after startup completes.
+		fx.Invoke(func(lc fx.Lifecycle, log *zap.Logger, sd fx.Shutdowner) {
+			if latch == nil {
+				return
+			}
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					latch.Stop()
+					if sig := latch.Signal(); sig != nil {
+						log.Info("shutdown: signal latched", zap.Stringer("signal", sig))
+						return sd.Shutdown()
+					}
+					return nil
+				},
+			})
+		}),
 	)
 }
 
+// goroutineStacks returns the stacks of all goroutines, growing the buffer
+// until the dump fits (capped at 64 MiB).
+func goroutineStacks() string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
 // Go runs fn in a managed goroutine tied to the shared WaitGroup.
 // Use this for background work that must complete or exit on shutdown.
 func Go(wg *sync.WaitGroup, fn func()) {
@@ -82,3 +202,36 @@ func Go(wg *sync.WaitGroup, fn func()) {
 		fn()
 	}()
 }
+
+// Workers starts background goroutines tied to the shared WaitGroup.
+// Unlike Go, it can recover worker panics and log them.
+type Workers struct {
+	wg  *sync.WaitGroup
+	log *zap.Logger
+}
+
+// NewWorkers returns a Workers tracking goroutines on wg.
+func NewWorkers(wg *sync.WaitGroup, log *zap.Logger) *Workers {
+	return &Workers{wg: wg, log: log}
+}
+
+// Go runs fn in a managed goroutine, like the package-level Go.
+func (w *Workers) Go(fn func()) {
+	Go(w.wg, fn)
+}
+
+// GoSafe runs fn in a managed goroutine and recovers a panic in fn instead of
+// crashing the process. The panic is logged at error level with its stack and
+// the WaitGroup is still released, so shutdown is not blocked by a dead worker.
+func (w *Workers) GoSafe(fn func()) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				w.log.Error("shutdown: worker panicked", zap.Any("panic", r), zap.Stack("stack"))
+			}
+		}()
+		fn()
+	}()
+}
@@ -2,7 +2,12 @@ package shutdownkit_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -10,7 +15,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type ShutdownDeps struct {
@@ -101,3 +108,207 @@ func TestShutdownLifecycle(t *testing.T) {
 		t.Fatal("expected graceful context to be cancelled during Stop")
 	}
 }
+
+func TestPreStopDelay(t *testing.T) {
+	var sd ShutdownDeps
+	app := fx.New(
+		shutdownkit.Module(shutdownkit.WithPreStopDelay(150*time.Millisecond)),
+		fx.Provide(func() *zap.Logger { return zaptest.NewLogger(t) }),
+		fx.Invoke(func(d ShutdownDeps) { sd = d }),
+	)
+	require.NoError(t, app.Start(context.Background()))
+
+	start := time.Now()
+	stopped := make(chan error, 1)
+	go func() { stopped <- app.Stop(context.Background()) }()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, sd.Graceful.Err(), "graceful must wait for the pre-stop delay")
+	require.NoError(t, <-stopped)
+	require.Error(t, sd.Graceful.Err())
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestPreStopDelay_BoundedByStopDeadline(t *testing.T) {
+	app := fx.New(
+		shutdownkit.Module(shutdownkit.WithPreStopDelay(time.Minute)),
+		fx.Provide(func() *zap.Logger { return zaptest.NewLogger(t) }),
+	)
+	require.NoError(t, app.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_ = app.Stop(ctx)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestStackDumpOnForce(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+
+	app := fx.New(
+		fx.NopLogger,
+		shutdownkit.Module(shutdownkit.WithTimeout(20*time.Millisecond), shutdownkit.WithStackDumpOnForce(true)),
+		fx.Provide(func() *zap.Logger { return zap.New(core) }),
+		fx.Invoke(func(d ShutdownDeps) {
+			// A worker that ignores graceful and only exits shortly after force.
+			shutdownkit.Go(d.WG, func() {
+				<-d.Force.Done()
+				time.Sleep(50 * time.Millisecond)
+			})
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, app.Start(ctx))
+	require.NoError(t, app.Stop(ctx))
+
+	dumps := logs.FilterMessage("shutdown: forced, dumping goroutines").All()
+	require.Len(t, dumps, 1)
+	require.Contains(t, dumps[0].ContextMap()["stacks"], "goroutine ")
+}
+
+func TestWorkers_GoSafeRecoversPanic(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	wg := &sync.WaitGroup{}
+	w := shutdownkit.NewWorkers(wg, zap.New(core))
+
+	w.GoSafe(func() { panic("boom") })
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WaitGroup not released after worker panic")
+	}
+
+	entries := logs.FilterMessage("shutdown: worker panicked").All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "boom", entries[0].ContextMap()["panic"])
+	require.Contains(t, entries[0].ContextMap()["stack"], "TestWorkers_GoSafeRecoversPanic")
+}
+
+func TestModule_ProvidesWorkers(t *testing.T) {
+	ran := make(chan struct{})
+	app := fx.New(
+		fx.NopLogger,
+		shutdownkit.Module(),
+		fx.Provide(zap.NewNop),
+		fx.Invoke(func(w *shutdownkit.Workers) {
+			w.Go(func() { close(ran) })
+		}),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, app.Start(ctx))
+	require.NoError(t, app.Stop(ctx))
+	<-ran
+}
+
+func TestSignalDuringStartup_ShutsDownAfterStart(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestStartupSignalChildHelper", "--", "child")
+	cmd.Env = append(os.Environ(), "RUN_STARTUP_SIGNAL_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("child failed: %v; out=%s", err, string(out))
+	}
+	require.Contains(t, string(out), "child:start-completed")
+	require.Contains(t, string(out), "child:shutdown-after-start")
+}
+
+// TestStartupSignalChildHelper is invoked as a subprocess by
+// TestSignalDuringStartup_ShutsDownAfterStart. An OnStart hook sends SIGTERM
+// to the process mid-start; without the latch the process would be killed.
+func TestStartupSignalChildHelper(t *testing.T) {
+	if os.Getenv("RUN_STARTUP_SIGNAL_CHILD") != "1" {
+		t.Skip("helper")
+	}
+
+	// Module is listed after the hook so the latch covers it.
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(zap.NewNop),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{OnStart: func(context.Context) error {
+				if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+					return err
+				}
+				// Keep "starting" for a moment so the signal lands mid-start.
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			}})
+		}),
+		shutdownkit.Module(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := app.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "child:start-err:%v\n", err)
+		os.Exit(2)
+	}
+	fmt.Fprintln(os.Stdout, "child:start-completed") //nolint:errcheck
+
+	select {
+	case <-app.Wait():
+		fmt.Fprintln(os.Stdout, "child:shutdown-after-start") //nolint:errcheck
+	case <-time.After(time.Second):
+		fmt.Fprintln(os.Stderr, "child:no-shutdown-signal")
+		os.Exit(3)
+	}
+	if err := app.Stop(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "child:stop-err:%v\n", err)
+		os.Exit(4)
+	}
+}
+
+func TestStartupFailure_DisarmsLatch(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestStartupFailureChildHelper", "--", "child")
+	cmd.Env = append(os.Environ(), "RUN_STARTUP_FAILURE_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	require.NotContains(t, string(out), "child:survived")
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr, "out=%s", out)
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	require.True(t, ok)
+	require.True(t, status.Signaled(), "child should die by signal; out=%s", out)
+	require.Equal(t, syscall.SIGTERM, status.Signal())
+}
+
+// TestStartupFailureChildHelper is invoked as a subprocess by
+// TestStartupFailure_DisarmsLatch. Startup fails before the latch is handed
+// off; a SIGTERM sent afterwards must take its default action again.
+func TestStartupFailureChildHelper(t *testing.T) {
+	if os.Getenv("RUN_STARTUP_FAILURE_CHILD") != "1" {
+		t.Skip("helper")
+	}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(zap.NewNop),
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{OnStart: func(context.Context) error {
+				return errors.New("boom")
+			}})
+		}),
+		shutdownkit.Module(),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := app.Start(ctx); err == nil {
+		fmt.Fprintln(os.Stderr, "child:started")
+		os.Exit(2)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		fmt.Fprintf(os.Stderr, "child:kill-err:%v\n", err)
+		os.Exit(3)
+	}
+	time.Sleep(time.Second)
+	fmt.Fprintln(os.Stdout, "child:survived") //nolint:errcheck
+}
@@ -0,0 +1,89 @@
+package shutdownkit
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+// Hook identifies a lifecycle hook by the function that registered it
+// (Caller, e.g. "github.com/froppa/stackkit/kits/httpkit.registerHTTPServer")
+// and the hook function itself.
+type Hook struct {
+	Caller   string
+	Function string
+}
+
+// LifecycleRecorder records the order in which Fx runs OnStart and OnStop
+// hooks, so integration tests can assert on teardown order (for example, that
+// readiness flips before the HTTP server stops). It is test support; include
+// it with Option and inspect it after the app stops.
+type LifecycleRecorder struct {
+	next fxevent.Logger
+
+	mu     sync.Mutex
+	starts []Hook
+	stops  []Hook
+}
+
+// NewLifecycleRecorder returns a recorder that forwards every event to next,
+// or discards them when next is nil.
+func NewLifecycleRecorder(next fxevent.Logger) *LifecycleRecorder {
+	if next == nil {
+		next = fxevent.NopLogger
+	}
+	return &LifecycleRecorder{next: next}
+}
+
+// Option installs r as the app's Fx event logger. It replaces any other
+// fx.WithLogger; pass that logger to NewLifecycleRecorder instead.
+func (r *LifecycleRecorder) Option() fx.Option {
+	return fx.WithLogger(func() fxevent.Logger { return r })
+}
+
+// LogEvent implements fxevent.Logger.
+func (r *LifecycleRecorder) LogEvent(e fxevent.Event) {
+	switch e := e.(type) {
+	case *fxevent.OnStartExecuting:
+		r.mu.Lock()
+		r.starts = append(r.starts, Hook{Caller: e.CallerName, Function: e.FunctionName})
+		r.mu.Unlock()
+	case *fxevent.OnStopExecuting:
+		r.mu.Lock()
+		r.stops = append(r.stops, Hook{Caller: e.CallerName, Function: e.FunctionName})
+		r.mu.Unlock()
+	}
+	r.next.LogEvent(e)
+}
+
+// Starts returns the OnStart hooks in the order they ran.
+func (r *LifecycleRecorder) Starts() []Hook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Hook(nil), r.starts...)
+}
+
+// Stops returns the OnStop hooks in the order they ran.
+func (r *LifecycleRecorder) Stops() []Hook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Hook(nil), r.stops...)
+}
+
+// StoppedBefore reports whether the first OnStop hook whose caller contains a
+// ran before the first one whose caller contains b. It is false if either is
+// missing.
+func (r *LifecycleRecorder) StoppedBefore(a, b string) bool {
+	ia, ib := -1, -1
+	for i, h := range r.Stops() {
+		if ia < 0 && strings.Contains(h.Caller, a) {
+			ia = i
+		}
+		if ib < 0 && strings.Contains(h.Caller, b) {
+			ib = i
+		}
+	}
+	return ia >= 0 && ib >= 0 && ia < ib
+}
@@ -0,0 +1,52 @@
+package shutdownkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/shutdownkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+func registerServer(lc fx.Lifecycle) {
+	lc.Append(fx.StartStopHook(func() {}, func() {}))
+}
+
+func registerReadiness(lc fx.Lifecycle) {
+	lc.Append(fx.StopHook(func() {}))
+}
+
+type countingLogger struct{ n int }
+
+func (c *countingLogger) LogEvent(fxevent.Event) { c.n++ }
+
+func TestLifecycleRecorder_RecordsStopOrder(t *testing.T) {
+	next := &countingLogger{}
+	rec := shutdownkit.NewLifecycleRecorder(next)
+
+	app := fx.New(
+		rec.Option(),
+		shutdownkit.Module(),
+		fx.Provide(zap.NewNop),
+		fx.Invoke(registerServer),
+		fx.Invoke(registerReadiness),
+	)
+	require.NoError(t, app.Err())
+	require.NoError(t, app.Start(context.Background()))
+	require.NoError(t, app.Stop(context.Background()))
+
+	require.True(t, rec.StoppedBefore("registerReadiness", "registerServer"))
+	require.False(t, rec.StoppedBefore("registerServer", "registerReadiness"))
+	require.False(t, rec.StoppedBefore("registerReadiness", "missing"))
+
+	stops := rec.Stops()
+	require.Len(t, stops, 4)
+	// Module's signal latch hands off in a start hook of its own.
+	require.Len(t, rec.Starts(), 2)
+	require.Contains(t, rec.Starts()[1].Caller, "registerServer")
+	require.Contains(t, stops[0].Caller, "registerReadiness")
+	require.Positive(t, next.n, "events are forwarded")
+}
@@ -118,3 +118,63 @@ func (s *Shutdown) Wait(timeout time.Duration) {
 		<-done
 	}
 }
+
+// Latch records the first SIGINT/SIGTERM received while it is armed.
+//
+// It covers windows where nothing else listens for signals yet, such as
+// application startup: without a handler, the default action would kill a
+// half-initialized process. Once a signal is latched, the owner decides when
+// to act on it (e.g. after startup completes).
+type Latch struct {
+	ch     chan os.Signal
+	done   chan struct{}
+	stop   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+	sig    os.Signal
+}
+
+// NewLatch arms a Latch for SIGINT/SIGTERM. Call Stop to disarm it.
+func NewLatch() *Latch {
+	l := &Latch{
+		ch:     make(chan os.Signal, 1),
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	signal.Notify(l.ch, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer close(l.exited)
+		defer signal.Stop(l.ch)
+		select {
+		case s := <-l.ch:
+			l.sig = s
+			close(l.done)
+		case <-l.stop:
+		}
+	}()
+	return l
+}
+
+// Done is closed once a signal has been latched.
+func (l *Latch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Signal returns the latched signal, or nil if none was received.
+func (l *Latch) Signal() os.Signal {
+	select {
+	case <-l.done:
+		return l.sig
+	default:
+		return nil
+	}
+}
+
+// Stop disarms the latch and returns once signals take their default action
+// again. A signal already latched is kept, and Signal reports it. Safe to
+// call more than once.
+func (l *Latch) Stop() {
+	l.once.Do(func() { close(l.stop) })
+	<-l.exited
+}
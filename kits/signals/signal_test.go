@@ -320,3 +320,51 @@ func TestWait_AwaitsMultipleWorkersWithinTimeout(t *testing.T) {
 	require.NoError(t, s.Force().Err())
 	require.Less(t, time.Since(start), 150*time.Millisecond)
 }
+
+func TestLatch_StopWithoutSignal(t *testing.T) {
+	t.Parallel()
+
+	l := sig.NewLatch()
+	l.Stop()
+	l.Stop()
+
+	require.Nil(t, l.Signal())
+	select {
+	case <-l.Done():
+		t.Fatal("latch must not report a signal")
+	default:
+	}
+}
+
+func TestLatch_RecordsSignal(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestLatchChildHelper", "--", "child")
+	cmd.Env = append(os.Environ(), "RUN_LATCH_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("child failed: %v; out=%s", err, string(out))
+	}
+	require.Contains(t, string(out), "child:latched:terminated")
+}
+
+// TestLatchChildHelper is invoked as a subprocess by TestLatch_RecordsSignal.
+func TestLatchChildHelper(t *testing.T) {
+	if os.Getenv("RUN_LATCH_CHILD") != "1" {
+		t.Skip("helper")
+	}
+
+	l := sig.NewLatch()
+	defer l.Stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		fmt.Fprintf(os.Stderr, "child:kill-err:%v\n", err)
+		os.Exit(2)
+	}
+
+	select {
+	case <-l.Done():
+		fmt.Fprintf(os.Stdout, "child:latched:%v\n", l.Signal()) //nolint:errcheck
+	case <-time.After(250 * time.Millisecond):
+		fmt.Fprintln(os.Stderr, "child:timeout-waiting-latch")
+		os.Exit(3)
+	}
+}
@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/froppa/stackkit/kits/runtimeinfo"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// buildInfoProcessor stamps build metadata onto every span as it starts.
+type buildInfoProcessor struct {
+	attrs []attribute.KeyValue
+}
+
+func newBuildInfoProcessor(cfg Config) sdktrace.SpanProcessor {
+	var attrs []attribute.KeyValue
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(cfg.ServiceVersion))
+	}
+	if runtimeinfo.Commit != "" {
+		attrs = append(attrs, attribute.String("vcs.revision", runtimeinfo.Commit))
+	}
+	return buildInfoProcessor{attrs: attrs}
+}
+
+func (p buildInfoProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	s.SetAttributes(p.attrs...)
+}
+
+func (buildInfoProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (buildInfoProcessor) Shutdown(context.Context) error   { return nil }
+func (buildInfoProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,56 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	info "github.com/froppa/stackkit/kits/runtimeinfo"
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBuildInfoProcessorStampsSpans(t *testing.T) {
+	origMeta := snapshotInfo()
+	defer restoreInfo(origMeta)
+	info.Commit = "abc123"
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(newBuildInfoProcessor(Config{ServiceVersion: "v1.2.3"})),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	attrs := spans[0].Attributes()
+	if !attrEquals(attrs, attribute.Key("service.version"), "v1.2.3") {
+		t.Fatalf("missing service.version on span: %v", attrs)
+	}
+	if !attrEquals(attrs, attribute.Key("vcs.revision"), "abc123") {
+		t.Fatalf("missing vcs.revision on span: %v", attrs)
+	}
+}
+
+func TestBuildTracerProviderSpanBuildInfo(t *testing.T) {
+	tracing := true
+	cfg := Config{TracingEnabled: &tracing, TraceSampleRate: 1, ServiceVersion: "v9", SpanBuildInfo: true}
+	tp, err := buildTracerProvider(context.Background(), cfg, sdkresource.NewSchemaless(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	ro, ok := span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		t.Fatalf("expected sdk span")
+	}
+	if !attrEquals(ro.Attributes(), attribute.Key("service.version"), "v9") {
+		t.Fatalf("expected span build info when enabled, got %v", ro.Attributes())
+	}
+}
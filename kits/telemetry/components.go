@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+// TracerFor returns an Fx constructor for a tracer from the module's
+// provider whose instrumentation scope is component (conventionally the
+// package path), so backends attribute spans to the component instead of the
+// whole service.
+func TracerFor(component string) func(tp *sdktrace.TracerProvider) trace.Tracer {
+	return func(tp *sdktrace.TracerProvider) trace.Tracer { return tp.Tracer(component) }
+}
+
+// MeterFor is TracerFor for meters.
+func MeterFor(component string) func(mp *sdkmetric.MeterProvider) metric.Meter {
+	return func(mp *sdkmetric.MeterProvider) metric.Meter { return mp.Meter(component) }
+}
+
+// TracerName is the Fx name under which Components provides component's
+// tracer.
+func TracerName(component string) string { return "telemetry.tracer." + component }
+
+// MeterName is the Fx name under which Components provides component's meter.
+func MeterName(component string) string { return "telemetry.meter." + component }
+
+// Components provides a named trace.Tracer and metric.Meter per component,
+// built with TracerFor and MeterFor. It requires Module. Consumers select
+// theirs by name:
+//
+//	telemetry.Components("billing", "search")
+//
+//	fx.Provide(fx.Annotate(NewBilling, fx.ParamTags(
+//	    `name:"telemetry.tracer.billing"`, `name:"telemetry.meter.billing"`)))
+func Components(components ...string) fx.Option {
+	opts := make([]fx.Option, 0, len(components))
+	for _, c := range components {
+		opts = append(opts, fx.Provide(
+			fx.Annotate(TracerFor(c), fx.ResultTags(`name:"`+TracerName(c)+`"`)),
+			fx.Annotate(MeterFor(c), fx.ResultTags(`name:"`+MeterName(c)+`"`)),
+		))
+	}
+	return fx.Options(opts...)
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestComponents(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	type deps struct {
+		fx.In
+		Tracer trace.Tracer `name:"telemetry.tracer.billing"`
+		Meter  metric.Meter `name:"telemetry.meter.search"`
+	}
+	var d deps
+	app := fxtest.New(t,
+		fx.Supply(tp, mp),
+		Components("billing", "search"),
+		fx.Populate(&d),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	_, span := d.Tracer.Start(context.Background(), "charge")
+	span.End()
+	require.Len(t, spans.Ended(), 1)
+	require.Equal(t, "billing", spans.Ended()[0].InstrumentationScope().Name)
+
+	counter, err := d.Meter.Int64Counter("queries")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Equal(t, "search", rm.ScopeMetrics[0].Scope.Name)
+}
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+)
+
+// ConfigReloads is an opt-in option that exports the configkit reload
+// counters (see configkit.RecordReload) as metrics:
+//
+//   - `config_reload_total`: reload attempts, failed or not
+//   - `config_reload_errors_total`: failed attempts
+//   - `config_last_reload_timestamp`: Unix seconds of the last attempt, 0
+//     before the first
+//
+// It does nothing when no metric.Meter is in the container; reloads are
+// still logged by configkit.
+func ConfigReloads() fx.Option {
+	return fx.Invoke(registerConfigReloads)
+}
+
+type configReloadDeps struct {
+	fx.In
+
+	Meter metric.Meter `optional:"true"`
+	LC    fx.Lifecycle
+}
+
+func registerConfigReloads(d configReloadDeps) error {
+	if d.Meter == nil {
+		return nil
+	}
+	total, err := d.Meter.Int64ObservableCounter("config_reload_total",
+		metric.WithDescription("Config reload attempts."),
+	)
+	if err != nil {
+		return err
+	}
+	errs, err := d.Meter.Int64ObservableCounter("config_reload_errors_total",
+		metric.WithDescription("Config reload attempts that failed."),
+	)
+	if err != nil {
+		return err
+	}
+	last, err := d.Meter.Int64ObservableGauge("config_last_reload_timestamp",
+		metric.WithDescription("Unix time of the last config reload attempt."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+	reg, err := d.Meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := configkit.Reloads()
+		o.ObserveInt64(total, int64(s.Total))
+		o.ObserveInt64(errs, int64(s.Errors))
+		var ts int64
+		if !s.Last.IsZero() {
+			ts = s.Last.Unix()
+		}
+		o.ObserveInt64(last, ts)
+		return nil
+	}, total, errs, last)
+	if err != nil {
+		return err
+	}
+
+	d.LC.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return reg.Unregister()
+		},
+	})
+	return nil
+}
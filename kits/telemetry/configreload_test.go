@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	fxtest "go.uber.org/fx/fxtest"
+)
+
+func TestConfigReloads(t *testing.T) {
+	configkit.ResetReloadsForTests()
+	t.Cleanup(configkit.ResetReloadsForTests)
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	lc := fxtest.NewLifecycle(t)
+	require.NoError(t, registerConfigReloads(configReloadDeps{Meter: mp.Meter("test"), LC: lc}))
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	collect := func() map[string]int64 {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		got := map[string]int64{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch data := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					got[m.Name] = data.DataPoints[0].Value
+				case metricdata.Gauge[int64]:
+					got[m.Name] = data.DataPoints[0].Value
+				}
+			}
+		}
+		return got
+	}
+
+	require.Equal(t, map[string]int64{
+		"config_reload_total":          0,
+		"config_reload_errors_total":   0,
+		"config_last_reload_timestamp": 0,
+	}, collect())
+
+	before := time.Now().Unix()
+	configkit.RecordReload(nil)
+	configkit.RecordReload(errors.New("bad yaml"))
+	got := collect()
+	require.Equal(t, int64(2), got["config_reload_total"])
+	require.Equal(t, int64(1), got["config_reload_errors_total"])
+	require.GreaterOrEqual(t, got["config_last_reload_timestamp"], before)
+	require.EqualError(t, configkit.Reloads().LastErr, "bad yaml")
+}
+
+func TestConfigReloadsWithoutMeter(t *testing.T) {
+	require.NoError(t, registerConfigReloads(configReloadDeps{LC: fxtest.NewLifecycle(t)}))
+}
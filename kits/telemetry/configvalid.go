@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ConfigValidation is an opt-in option that validates every known config
+// module (configkit.ValidateAll) at startup and reports the outcome as the
+// gauge `config_valid{key=...}`: 1 when the subtree populated, validated,
+// and had no unknown keys, 0 otherwise. Startup is not failed by invalid
+// config here; the gauge is meant for fleet-wide dashboards.
+//
+// It needs a configkit.Provider (configkit.Module) and does nothing when no
+// metric.Meter is in the container.
+func ConfigValidation() fx.Option {
+	return fx.Invoke(registerConfigValidation)
+}
+
+type configValidationDeps struct {
+	fx.In
+
+	Provider configkit.Provider
+	Meter    metric.Meter `optional:"true"`
+	Logger   *zap.Logger
+	LC       fx.Lifecycle
+}
+
+func registerConfigValidation(d configValidationDeps) error {
+	if d.Meter == nil {
+		return nil
+	}
+	// Set on start and read by the collecting goroutine.
+	var results atomic.Pointer[[]configkit.CheckResult]
+	gauge, err := d.Meter.Int64ObservableGauge("config_valid",
+		metric.WithDescription("Whether each known config key validated at startup (1) or not (0)."),
+	)
+	if err != nil {
+		return err
+	}
+	reg, err := d.Meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		rs := results.Load()
+		if rs == nil {
+			return nil
+		}
+		for _, r := range *rs {
+			v := int64(0)
+			if r.OK {
+				v = 1
+			}
+			o.ObserveInt64(gauge, v, metric.WithAttributes(attribute.String("key", r.Key)))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		return err
+	}
+
+	d.LC.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			rs, verr := configkit.ValidateAll(d.Provider)
+			results.Store(&rs)
+			if verr != nil {
+				d.Logger.Warn("config validation failed", zap.Error(verr))
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			return reg.Unregister()
+		},
+	})
+	return nil
+}
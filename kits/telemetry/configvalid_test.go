@@ -0,0 +1,69 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	uber "go.uber.org/config"
+	fxtest "go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func TestConfigValidation(t *testing.T) {
+	configkit.ResetDiscoveryForTests()
+	t.Cleanup(configkit.ResetDiscoveryForTests)
+	type good struct {
+		Name string `yaml:"name" validate:"required"`
+	}
+	type bad struct {
+		Port int `yaml:"port" validate:"min=1"`
+	}
+	configkit.RegisterKnown("good", good{})
+	configkit.RegisterKnown("bad", bad{})
+
+	p, err := uber.NewYAML(uber.Source(strings.NewReader("good:\n  name: x\nbad:\n  port: 0\n")))
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	lc := fxtest.NewLifecycle(t)
+	require.NoError(t, registerConfigValidation(configValidationDeps{
+		Provider: p,
+		Meter:    mp.Meter("test"),
+		Logger:   zap.NewNop(),
+		LC:       lc,
+	}))
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "config_valid" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+				key, _ := dp.Attributes.Value("key")
+				got[key.AsString()] = dp.Value
+			}
+		}
+	}
+	require.Equal(t, int64(1), got["good"])
+	require.Contains(t, got, "bad")
+	require.Equal(t, int64(0), got["bad"])
+}
+
+func TestConfigValidationWithoutMeter(t *testing.T) {
+	p, err := uber.NewYAML(uber.Source(strings.NewReader("{}")))
+	require.NoError(t, err)
+	require.NoError(t, registerConfigValidation(configValidationDeps{Provider: p, Logger: zap.NewNop(), LC: fxtest.NewLifecycle(t)}))
+}
@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"context"
+	"regexp"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// excludeProcessor forwards ended spans to next unless their name matches one
+// of the excluded patterns, so matching spans never reach the exporter.
+type excludeProcessor struct {
+	next     sdktrace.SpanProcessor
+	patterns []*regexp.Regexp
+}
+
+// newExcludeProcessor wraps next, dropping spans whose name matches any of
+// patterns. Patterns use the same "*" globbing as SamplingRule.NamePattern.
+func newExcludeProcessor(patterns []string, next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	p := excludeProcessor{next: next}
+	for _, pat := range patterns {
+		p.patterns = append(p.patterns, globRegexp(pat))
+	}
+	return p
+}
+
+func (p excludeProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd checks the final span name, since instrumentation may rename a span
+// after it starts (e.g. once the route is known).
+func (p excludeProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	for _, re := range p.patterns {
+		if re.MatchString(s.Name()) {
+			return
+		}
+	}
+	p.next.OnEnd(s)
+}
+
+func (p excludeProcessor) Shutdown(ctx context.Context) error   { return p.next.Shutdown(ctx) }
+func (p excludeProcessor) ForceFlush(ctx context.Context) error { return p.next.ForceFlush(ctx) }
@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExcludeProcessorDropsMatchingSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(newExcludeProcessor([]string{"GET /health*", "*.ping"}, recorder)),
+	)
+	tr := tp.Tracer("test")
+	for _, name := range []string{"GET /health", "GET /healthz", "db.ping", "GET /orders"} {
+		_, span := tr.Start(context.Background(), name)
+		span.End()
+	}
+	// A span renamed after start is judged by its final name.
+	_, span := tr.Start(context.Background(), "GET")
+	span.SetName("GET /health")
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Name() != "GET /orders" {
+		var names []string
+		for _, s := range ended {
+			names = append(names, s.Name())
+		}
+		t.Fatalf("expected only GET /orders to be exported, got %v", names)
+	}
+}
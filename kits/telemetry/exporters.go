@@ -0,0 +1,177 @@
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+// Exporters accepted by Config.Exporter.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// consoleOut is where the stdout exporters write.
+var consoleOut io.Writer = os.Stdout
+
+// OTLP transport protocols accepted by Config.Protocol.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// exporting reports whether cfg selects an exporter that can send data for a
+// signal whose OTLP endpoint is endpoint: stdout always can, OTLP only with an
+// endpoint.
+func exporting(cfg Config, endpoint string) bool {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		return true
+	case ExporterNone:
+		return false
+	default:
+		return endpoint != ""
+	}
+}
+
+// traceEndpoint returns the OTLP endpoint for traces.
+func traceEndpoint(cfg Config) string { return cmp.Or(cfg.TraceEndpoint, cfg.OTLPEndpoint) }
+
+// metricEndpoint returns the OTLP endpoint for metrics.
+func metricEndpoint(cfg Config) string { return cmp.Or(cfg.MetricEndpoint, cfg.OTLPEndpoint) }
+
+// newTraceExporter returns the span exporter for cfg.Exporter, over
+// cfg.Protocol for OTLP. A non-nil conn is used by the gRPC exporter instead
+// of dialing the endpoint.
+func newTraceExporter(ctx context.Context, cfg Config, conn *grpc.ClientConn) (sdktrace.SpanExporter, error) {
+	if cfg.Exporter == ExporterStdout {
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(consoleOut), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("stdout trace exporter: %w", err)
+		}
+		return exp, nil
+	}
+	if cfg.Protocol == ProtocolHTTPProtobuf {
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(traceEndpoint(cfg))}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("otlp trace exporter: %w", err)
+		}
+		return exp, nil
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(traceEndpoint(cfg))}
+	if conn != nil {
+		opts = []otlptracegrpc.Option{otlptracegrpc.WithGRPCConn(conn)}
+	} else if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	exp, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlp trace exporter: %w", err)
+	}
+	return exp, nil
+}
+
+// newMetricExporter returns the metric exporter for cfg.Exporter, over
+// cfg.Protocol for OTLP. A non-nil conn is used by the gRPC exporter instead
+// of dialing the endpoint.
+func newMetricExporter(ctx context.Context, cfg Config, conn *grpc.ClientConn) (sdkmetric.Exporter, error) {
+	if cfg.Exporter == ExporterStdout {
+		exp, err := stdoutmetric.New(stdoutmetric.WithWriter(consoleOut), stdoutmetric.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("stdout metric exporter: %w", err)
+		}
+		return exp, nil
+	}
+	if cfg.Protocol == ProtocolHTTPProtobuf {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(metricEndpoint(cfg))}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		exp, err := otlpmetrichttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("otlp metric exporter: %w", err)
+		}
+		return exp, nil
+	}
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(metricEndpoint(cfg))}
+	if conn != nil {
+		opts = []otlpmetricgrpc.Option{otlpmetricgrpc.WithGRPCConn(conn)}
+	} else if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+	exp, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlp metric exporter: %w", err)
+	}
+	return exp, nil
+}
+
+// parseHeaders parses OTEL_EXPORTER_OTLP_HEADERS: comma-separated key=value
+// pairs with percent-encoded values. Malformed pairs are skipped.
+func parseHeaders(s string) map[string]string {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		val, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		out[k] = val
+	}
+	return out
+}
+
+// mergeHeaders returns base with override's entries set on top.
+func mergeHeaders(base, override map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(override))
+	maps.Copy(out, base)
+	maps.Copy(out, override)
+	return out
+}
+
+// redactedHeaders lists the header names with masked values, for logging.
+func redactedHeaders(h map[string]string) []string {
+	out := make([]string, 0, len(h))
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		out = append(out, k+"=***")
+	}
+	return out
+}
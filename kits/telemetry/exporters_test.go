@@ -0,0 +1,258 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewProvidersHTTPProtobuf(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths = map[string]string{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		paths[r.URL.Path] = r.Header.Get("Content-Type")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		OTLPEndpoint:     strings.TrimPrefix(srv.URL, "http://"),
+		Insecure:         true,
+		Protocol:         ProtocolHTTPProtobuf,
+		SharedConnection: true, // gRPC only; ignored
+	}
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if out.SharedConn != nil {
+		t.Fatalf("expected no shared gRPC connection over http/protobuf")
+	}
+
+	_, span := out.Tracer.Start(context.Background(), "op")
+	span.End()
+	counter, err := out.Meter.Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+
+	if err := out.TracerProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown tracer: %v", err)
+	}
+	if err := out.MeterProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown meter: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, p := range []string{"/v1/traces", "/v1/metrics"} {
+		if ct, ok := paths[p]; !ok || ct != "application/x-protobuf" {
+			t.Fatalf("expected protobuf export to %s, got %v", p, paths)
+		}
+	}
+}
+
+func TestApplyConfigDefaultsProtocol(t *testing.T) {
+	cfg := &Config{}
+	applyConfigDefaults(cfg)
+	if cfg.Protocol != ProtocolGRPC {
+		t.Fatalf("expected grpc by default, got %q", cfg.Protocol)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	cfg = &Config{Protocol: ProtocolGRPC}
+	applyConfigDefaults(cfg)
+	if cfg.Protocol != ProtocolHTTPProtobuf {
+		t.Fatalf("expected env to override protocol, got %q", cfg.Protocol)
+	}
+}
+
+func TestNewProvidersRejectsUnknownEnvProtocol(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grcp")
+	_, err := NewProviders(context.Background(), &Config{OTLPEndpoint: "localhost:4317"}, zap.NewNop())
+	if err == nil || !strings.Contains(err.Error(), `OTEL_EXPORTER_OTLP_PROTOCOL: unsupported protocol "grcp"`) {
+		t.Fatalf("expected an OTEL_EXPORTER_OTLP_PROTOCOL error, got %v", err)
+	}
+}
+
+func TestNewProvidersStdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	consoleOut = &buf
+	defer func() { consoleOut = os.Stdout }()
+
+	// No endpoint: stdout alone enables both signals.
+	cfg := &Config{Exporter: ExporterStdout}
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if !*cfg.TracingEnabled || !*cfg.MetricsEnabled {
+		t.Fatalf("expected stdout to enable tracing and metrics")
+	}
+
+	_, span := out.Tracer.Start(context.Background(), "local-op")
+	span.End()
+	counter, err := out.Meter.Int64Counter("local_requests")
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+	if err := out.TracerProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown tracer: %v", err)
+	}
+	if err := out.MeterProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown meter: %v", err)
+	}
+	printed := buf.String()
+	for _, want := range []string{`"Name": "local-op"`, `"Name": "local_requests"`} {
+		if !strings.Contains(printed, want) {
+			t.Fatalf("expected %s on stdout, got:\n%s", want, printed)
+		}
+	}
+}
+
+func TestApplyConfigDefaultsExporterNone(t *testing.T) {
+	cfg := &Config{Exporter: ExporterNone, OTLPEndpoint: "collector:4317"}
+	applyConfigDefaults(cfg)
+	if *cfg.TracingEnabled || *cfg.MetricsEnabled {
+		t.Fatalf("expected exporter none to leave tracing and metrics off")
+	}
+}
+
+func TestNewProvidersSendsHeaders(t *testing.T) {
+	got := make(chan http.Header, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		got <- r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20from-env,X-Tenant=acme")
+	core, logs := observer.New(zapcore.InfoLevel)
+	metrics := false
+	cfg := &Config{
+		OTLPEndpoint:   strings.TrimPrefix(srv.URL, "http://"),
+		Insecure:       true,
+		Protocol:       ProtocolHTTPProtobuf,
+		Headers:        map[string]string{"Authorization": "Bearer from-config", "X-Team": "payments"},
+		MetricsEnabled: &metrics,
+	}
+	out, err := NewProviders(context.Background(), cfg, zap.New(core))
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	_, span := out.Tracer.Start(context.Background(), "op")
+	span.End()
+	if err := out.TracerProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown tracer: %v", err)
+	}
+
+	h := <-got
+	for k, want := range map[string]string{
+		"Authorization": "Bearer from-env", // env wins per key
+		"X-Tenant":      "acme",
+		"X-Team":        "payments",
+	} {
+		if h.Get(k) != want {
+			t.Fatalf("header %s = %q, want %q", k, h.Get(k), want)
+		}
+	}
+
+	entry := logs.FilterMessage("telemetry initialized").All()[0]
+	logged := entry.ContextMap()["otlp.headers"]
+	want := []any{"Authorization=***", "X-Team=***", "X-Tenant=***"}
+	if !reflect.DeepEqual(logged, want) {
+		t.Fatalf("expected redacted headers %v in log, got %v", want, logged)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	got := parseHeaders(" api-key = a%3Db , broken, =x,x-empty=")
+	want := map[string]string{"api-key": "a=b", "x-empty": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseHeaders = %v, want %v", got, want)
+	}
+}
+
+func TestNewProvidersPerSignalEndpoints(t *testing.T) {
+	paths := func(ch chan string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			ch <- r.URL.Path
+		}))
+	}
+	traces, metrics := make(chan string, 4), make(chan string, 4)
+	traceSrv, metricSrv := paths(traces), paths(metrics)
+	defer traceSrv.Close()
+	defer metricSrv.Close()
+
+	cfg := &Config{
+		OTLPEndpoint:     "unused:4318",
+		TraceEndpoint:    strings.TrimPrefix(traceSrv.URL, "http://"),
+		Insecure:         true,
+		Protocol:         ProtocolHTTPProtobuf,
+		SharedConnection: true,
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", strings.TrimPrefix(metricSrv.URL, "http://"))
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if out.SharedConn != nil {
+		t.Fatalf("expected no shared connection across endpoints")
+	}
+
+	_, span := out.Tracer.Start(context.Background(), "op")
+	span.End()
+	counter, err := out.Meter.Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+	if err := out.TracerProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown tracer: %v", err)
+	}
+	if err := out.MeterProvider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown meter: %v", err)
+	}
+
+	if p := <-traces; p != "/v1/traces" || len(traces) != 0 {
+		t.Fatalf("expected only traces at the trace endpoint, got %s", p)
+	}
+	if p := <-metrics; p != "/v1/metrics" || len(metrics) != 0 {
+		t.Fatalf("expected only metrics at the metric endpoint, got %s", p)
+	}
+}
+
+func TestApplyConfigDefaultsSignalEndpoint(t *testing.T) {
+	// A signal endpoint alone enables only that signal.
+	cfg := &Config{TraceEndpoint: "traces:4317"}
+	applyConfigDefaults(cfg)
+	if !*cfg.TracingEnabled || *cfg.MetricsEnabled {
+		t.Fatalf("expected only tracing enabled, got tracing=%v metrics=%v", *cfg.TracingEnabled, *cfg.MetricsEnabled)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "env-traces:4317")
+	cfg = &Config{OTLPEndpoint: "collector:4317", TraceEndpoint: "traces:4317"}
+	applyConfigDefaults(cfg)
+	if traceEndpoint(*cfg) != "env-traces:4317" || metricEndpoint(*cfg) != "collector:4317" {
+		t.Fatalf("unexpected endpoints %q and %q", traceEndpoint(*cfg), metricEndpoint(*cfg))
+	}
+}
@@ -9,16 +9,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/httpkit"
 	"github.com/froppa/stackkit/kits/runtimeinfo"
+	otelruntime "go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -28,6 +29,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 func init() { configkit.RegisterKnown("telemetry", (*Config)(nil)) }
@@ -36,10 +38,11 @@ func init() { configkit.RegisterKnown("telemetry", (*Config)(nil)) }
 // It is the main entry point for using this package.
 func Module() fx.Option {
 	return fx.Options(
-		fx.Provide(configkit.ProvideFromKey[Config]("telemetry")),
-		fx.Provide(NewProviders),
+		fx.Provide(provideConfig(configkit.ProvideFromKey[Config]("telemetry"))),
+		fx.Provide(provideProviders),
 		fx.Invoke(registerShutdown),
 		fx.Invoke(installGlobals),
+		fx.Invoke(registerMetricsDump),
 	)
 }
 
@@ -47,18 +50,31 @@ type globalDeps struct {
 	fx.In
 	TracerProvider *sdktrace.TracerProvider
 	MeterProvider  *sdkmetric.MeterProvider
+	Propagator     propagation.TextMapPropagator `optional:"true"`
+	Cfg            *Config                       `optional:"true"`
 }
 
+// installGlobals registers the providers and the propagator with the otel
+// package, unless Config.SetGlobals is false.
 func installGlobals(d globalDeps) {
+	if d.Cfg != nil && d.Cfg.SetGlobals != nil && !*d.Cfg.SetGlobals {
+		return
+	}
 	if d.TracerProvider != nil {
 		otel.SetTracerProvider(d.TracerProvider)
 	}
 	if d.MeterProvider != nil {
 		otel.SetMeterProvider(d.MeterProvider)
 	}
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{}, propagation.Baggage{},
-	))
+	prop := d.Propagator
+	if prop == nil {
+		var names []string
+		if d.Cfg != nil {
+			names = d.Cfg.Propagators
+		}
+		prop = newPropagator(names)
+	}
+	otel.SetTextMapPropagator(prop)
 }
 
 // Config defines the settings for the OpenTelemetry module, loaded from a YAML file.
@@ -73,63 +89,196 @@ type Config struct {
 	// Environment is the deployment environment (e.g., "production", "staging").
 	Environment string `yaml:"environment" validate:"omitempty"`
 
+	// Exporter selects where traces and metrics go: "otlp" (default) sends
+	// them to OTLPEndpoint, "stdout" prints them for local development
+	// without a collector, and "none" exports nothing.
+	Exporter string `yaml:"exporter" validate:"omitempty,oneof=otlp stdout none"`
+
+	// MetricsExporter selects how metrics leave the process: "otlp" (default)
+	// pushes them through Exporter, "prometheus" serves them at MetricsPath
+	// for scraping instead (see Result.MetricsHandlers).
+	MetricsExporter string `yaml:"metrics_exporter" validate:"omitempty,oneof=otlp prometheus"`
+
+	// PrometheusRuntimeLabels adds the runtimeinfo attributes (service.name,
+	// service.version, vcs.revision, ...) as constant labels to every
+	// Prometheus series.
+	PrometheusRuntimeLabels bool `yaml:"prometheus_runtime_labels"`
+
 	// OTLPEndpoint is the host:port address of the OTLP collector.
-	// If set, OTLP/gRPC exporters for traces and metrics are enabled.
+	// If set, OTLP exporters for traces and metrics are enabled.
 	// Overridden by the OTEL_EXPORTER_OTLP_ENDPOINT environment variable.
 	OTLPEndpoint string `yaml:"otlp_endpoint" validate:"omitempty"`
 
+	// TraceEndpoint overrides OTLPEndpoint for traces.
+	// Overridden by the OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variable.
+	TraceEndpoint string `yaml:"trace_endpoint" validate:"omitempty"`
+
+	// MetricEndpoint overrides OTLPEndpoint for metrics.
+	// Overridden by the OTEL_EXPORTER_OTLP_METRICS_ENDPOINT environment variable.
+	MetricEndpoint string `yaml:"metric_endpoint" validate:"omitempty"`
+
+	// Protocol is the OTLP transport: "grpc" (default) or "http/protobuf".
+	// Overridden by the OTEL_EXPORTER_OTLP_PROTOCOL environment variable.
+	Protocol string `yaml:"protocol" validate:"omitempty,oneof=grpc http/protobuf"`
+
+	// Headers are sent with every OTLP export, e.g. an Authorization header
+	// for a managed collector. Entries from the OTEL_EXPORTER_OTLP_HEADERS
+	// environment variable override these per key. Values are never logged.
+	Headers map[string]string `yaml:"headers" secret:"true"`
+
 	// Insecure disables TLS when connecting to the OTLP endpoint.
 	Insecure bool `yaml:"insecure"`
 
+	// SharedConnection makes the trace and metric exporters share one gRPC
+	// connection to OTLPEndpoint instead of dialing one each. It is closed
+	// after both providers have shut down. Ignored with "http/protobuf" and
+	// when TraceEndpoint and MetricEndpoint differ.
+	SharedConnection bool `yaml:"shared_connection"`
+
 	// Disabled completely disables the OpenTelemetry SDK. If true, all other
 	// tracing and metrics settings are ignored, and no-op providers are configured.
 	// Overridden by the OTEL_SDK_DISABLED environment variable.
 	Disabled *bool `yaml:"disabled"`
 
 	// TracingEnabled explicitly enables or disables tracing.
-	// If this is not set, tracing is automatically enabled if OTLPEndpoint is present.
+	// If this is not set, tracing is automatically enabled if TraceEndpoint or
+	// OTLPEndpoint is present, or Exporter is "stdout".
 	// This is ignored if 'Disabled' is true.
 	TracingEnabled *bool `yaml:"tracing_enabled"`
 
 	// MetricsEnabled explicitly enables or disables metrics.
-	// If this is not set, metrics are automatically enabled if OTLPEndpoint is present.
+	// If this is not set, metrics are automatically enabled if MetricEndpoint
+	// or OTLPEndpoint is present, Exporter is "stdout", or MetricsExporter is
+	// "prometheus".
 	// This is ignored if 'Disabled' is true.
 	MetricsEnabled *bool `yaml:"metrics_enabled"`
 
+	// RuntimeMetrics reports Go runtime metrics (goroutines, heap, GC, ...)
+	// through the meter provider. Defaults to MetricsEnabled.
+	RuntimeMetrics *bool `yaml:"runtime_metrics"`
+
+	// SetGlobals installs the providers and the propagators as the otel
+	// globals. Defaults to true; set false for embedded use or parallel tests
+	// and inject Tracer/Meter, the providers, and the Propagator instead.
+	SetGlobals *bool `yaml:"set_globals"`
+
+	// Propagators selects the global propagators, combined in order, from
+	// "tracecontext", "baggage", "b3" (single header), "b3multi", and
+	// "jaeger"; "none" disables propagation. Defaults to tracecontext and
+	// baggage. Overridden by the OTEL_PROPAGATORS environment variable.
+	Propagators []string `yaml:"propagators" validate:"omitempty,dive,oneof=tracecontext baggage b3 b3multi jaeger none"`
+
 	// TraceSampler defines the sampling strategy.
-	// Valid options are "parent_ratio" (default), "always_on", "always_off".
-	TraceSampler string `yaml:"trace_sampler" validate:"omitempty,oneof=parent_ratio always_on always_off"`
+	// Valid options are "parent_ratio", "always_on", "always_off". When unset
+	// it is "parent_ratio" if TraceSampleRate is set, and otherwise depends on
+	// Environment: "always_on" for dev, development, local, test, staging, and
+	// stage; "parent_ratio" for anything else.
+	TraceSampler string `yaml:"trace_sampler" validate:"omitempty,ci,oneof=parent_ratio always_on always_off"`
 
 	// TraceSampleRate is the sampling rate for the "parent_ratio" sampler (e.g., 0.5 for 50%).
 	TraceSampleRate float64 `yaml:"trace_sample_rate" validate:"gte=0,lte=1"`
 
+	// SpanBuildInfo sets service.version and vcs.revision on every span at
+	// start, for backends that drop resource attributes on spans.
+	SpanBuildInfo bool `yaml:"span_build_info"`
+
+	// ExcludeSpans drops ended spans whose name matches any of these
+	// patterns before they are exported, e.g. "GET /health*" for probe
+	// traffic. "*" matches any run of characters, as in SamplingRule.
+	ExcludeSpans []string `yaml:"exclude_spans" validate:"omitempty,dive,required"`
+
+	// SamplingRules override TraceSampler for matching spans. The first matching
+	// rule wins; TraceSampler applies when none match.
+	SamplingRules []SamplingRule `yaml:"sampling_rules" validate:"omitempty,dive"`
+
+	// MetricsDumpSignal, if set (e.g. "SIGUSR2"), makes the process print a
+	// snapshot of all current metric values to stdout whenever it receives
+	// that signal. Intended for debugging without a collector.
+	MetricsDumpSignal string `yaml:"metrics_dump_signal" validate:"omitempty,oneof=SIGUSR1 SIGUSR2"`
+
 	// ExportInterval is the frequency at which metrics are exported.
 	ExportInterval time.Duration `yaml:"export_interval" validate:"gte=0"`
 
+	// ShutdownTimeout bounds how long flushing telemetry may take on stop.
+	// The Fx stop deadline still applies if it is shorter. Defaults to 15s.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" validate:"gte=0"`
+
+	// BestEffort keeps the app starting when one signal fails to initialize
+	// (e.g. a bad trace exporter): the error is logged and that signal gets a
+	// provider that exports nothing, while the other keeps working. By
+	// default any failure aborts startup.
+	BestEffort bool `yaml:"best_effort"`
+
 	// ResourceAttributes are additional key-value pairs to add to the resource identity.
 	ResourceAttributes map[string]string `yaml:"resource_attributes" validate:"omitempty,dive,keys,required,endkeys,required"`
+
+	// ConfigAttributes lists config paths (e.g. "telemetry.trace_sampler")
+	// whose values Module adds to the resource as "config.<path>", redacted
+	// via configkit.Summary. Keep it to a few small, non-secret keys.
+	ConfigAttributes []string `yaml:"config_attributes" validate:"omitempty,dive,required"`
+
+	// configValues holds the resolved ConfigAttributes; set by Module.
+	configValues map[string]string
+	// groupAttrs holds the ResourceGroup attributes; set by Module.
+	groupAttrs []attribute.KeyValue
+	// metricsServed reports that NewProviders serves MetricsPath.
+	metricsServed bool
+}
+
+// provideConfig wraps load to resolve Config.ConfigAttributes from the same
+// provider the config was read from.
+func provideConfig(load func(configkit.Provider) (*Config, error)) func(configkit.Provider) (*Config, error) {
+	return func(p configkit.Provider) (*Config, error) {
+		cfg, err := load(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(cfg.ConfigAttributes) > 0 {
+			cfg.configValues = configkit.Summary(p, cfg.ConfigAttributes)
+		}
+		return cfg, nil
+	}
 }
 
 // Result is an fx.Out struct that provides all OTEL components to the Fx container.
 // This allows other services to depend on specific components (e.g., trace.Tracer)
 // instead of a monolithic struct.
+//
+// Tracer and Meter are never nil: with `disabled: true` they are backed by
+// providers that sample nothing and export nothing, so consumers can inject
+// them without nil checks.
 type Result struct {
 	fx.Out
 	TracerProvider *sdktrace.TracerProvider
 	MeterProvider  *sdkmetric.MeterProvider
 	Tracer         trace.Tracer
 	Meter          metric.Meter
+	// Propagator combines Config.Propagators. It is provided whether or not
+	// SetGlobals installs it as the otel global.
+	Propagator  propagation.TextMapPropagator
+	MetricsDump *MetricsDump // nil unless MetricsDumpSignal is set
+	// SharedConn is nil unless SharedConnection is set and an exporter is
+	// enabled. It is named so it cannot collide with the app's own
+	// *grpc.ClientConn.
+	SharedConn *grpc.ClientConn `name:"telemetry.otlp_conn"`
+	// MetricsHandlers holds the Prometheus handler for MetricsPath when
+	// MetricsExporter is "prometheus", contributed to httpkit's
+	// `group:"http.handlers"`; it is empty otherwise.
+	MetricsHandlers []httpkit.Handler `group:"http.handlers,flatten"`
 }
 
 // NewProviders is an Fx constructor that builds the OTEL providers based on the loaded Config.
 // It is responsible for setting up the resource, exporters, and the tracer/meter providers.
-func NewProviders(ctx context.Context, cfg *Config, log *zap.Logger) (Result, error) {
-	out := Result{}
+func NewProviders(ctx context.Context, cfg *Config, log *zap.Logger) (out Result, err error) {
 	if cfg == nil {
 		return out, errors.New("telemetry config is nil")
 	}
 
+	if err := checkEnvOverrides(); err != nil {
+		return out, fmt.Errorf("telemetry: %w", err)
+	}
 	applyConfigDefaults(cfg)
+	out.Propagator = newPropagator(cfg.Propagators)
 
 	res, err := buildResource(*cfg)
 	if err != nil {
@@ -148,24 +297,83 @@ func NewProviders(ctx context.Context, cfg *Config, log *zap.Logger) (Result, er
 		return out, nil
 	}
 
-	tp, err := buildTracerProvider(ctx, *cfg, res)
+	conn, err := dialShared(*cfg)
+	if err != nil {
+		if !cfg.BestEffort {
+			return out, err
+		}
+		log.Error("telemetry: shared OTLP connection unavailable; dialing per exporter", zap.Error(err))
+	}
+	out.SharedConn = conn
+	if conn != nil {
+		// Nothing owns the connection until the providers are returned.
+		defer func() {
+			if err != nil {
+				_ = conn.Close()
+			}
+		}()
+	}
+
+	tp, err := buildTracerProvider(ctx, *cfg, res, conn)
 	if err != nil {
-		return out, err
+		if !cfg.BestEffort {
+			return out, err
+		}
+		log.Error("telemetry: tracing unavailable; continuing without it", zap.Error(err))
+		tp = sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.NeverSample()),
+			sdktrace.WithResource(res),
+		)
 	}
 	out.TracerProvider = tp
 	out.Tracer = tp.Tracer(cfg.ServiceName)
 
-	mp, err := buildMeterProvider(ctx, *cfg, res)
+	var readers []sdkmetric.Reader
+	if cfg.MetricsDumpSignal != "" {
+		out.MetricsDump = newMetricsDump(cfg.MetricsDumpSignal)
+		readers = append(readers, out.MetricsDump.reader)
+	}
+	if *cfg.MetricsEnabled && cfg.MetricsExporter == MetricsExporterPrometheus {
+		reader, h, err := newPrometheusReader(*cfg)
+		if err != nil {
+			if !cfg.BestEffort {
+				return out, err
+			}
+			log.Error("telemetry: prometheus metrics unavailable; continuing without them", zap.Error(err))
+		} else {
+			readers = append(readers, reader)
+			out.MetricsHandlers = []httpkit.Handler{h}
+			cfg.metricsServed = true
+		}
+	}
+	mp, err := buildMeterProvider(ctx, *cfg, res, conn, readers...)
 	if err != nil {
-		return out, err
+		if !cfg.BestEffort {
+			return out, err
+		}
+		log.Error("telemetry: metrics export unavailable; continuing without it", zap.Error(err))
+		mpOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+		for _, r := range readers {
+			mpOpts = append(mpOpts, sdkmetric.WithReader(r))
+		}
+		mp = sdkmetric.NewMeterProvider(mpOpts...)
 	}
 	out.MeterProvider = mp
 	out.Meter = mp.Meter(cfg.ServiceName)
+	// Collection stops when registerShutdown shuts the provider down.
+	if *cfg.RuntimeMetrics {
+		if err := otelruntime.Start(otelruntime.WithMeterProvider(mp)); err != nil {
+			if !cfg.BestEffort {
+				return out, fmt.Errorf("runtime metrics: %w", err)
+			}
+			log.Error("telemetry: runtime metrics unavailable; continuing without them", zap.Error(err))
+		}
+	}
 
-	if *cfg.TracingEnabled && cfg.OTLPEndpoint == "" {
+	if *cfg.TracingEnabled && cfg.Exporter == ExporterOTLP && traceEndpoint(*cfg) == "" {
 		log.Warn("tracing enabled but no OTLP endpoint set")
 	}
-	if *cfg.MetricsEnabled && cfg.OTLPEndpoint == "" {
+	if *cfg.MetricsEnabled && cfg.MetricsExporter == MetricsExporterOTLP && cfg.Exporter == ExporterOTLP && metricEndpoint(*cfg) == "" {
 		log.Warn("metrics enabled but no OTLP endpoint set")
 	}
 
@@ -176,11 +384,35 @@ func NewProviders(ctx context.Context, cfg *Config, log *zap.Logger) (Result, er
 		zap.Bool("sdk.disabled", *cfg.Disabled),
 		zap.Bool("tracing.enabled", *cfg.TracingEnabled),
 		zap.Bool("metrics.enabled", *cfg.MetricsEnabled),
+		zap.Bool("metrics.runtime", *cfg.RuntimeMetrics),
+		zap.String("exporter", cfg.Exporter),
+		zap.String("metrics.exporter", cfg.MetricsExporter),
 		zap.String("otlp.endpoint", cfg.OTLPEndpoint),
+		zap.String("otlp.trace_endpoint", traceEndpoint(*cfg)),
+		zap.String("otlp.metric_endpoint", metricEndpoint(*cfg)),
+		zap.String("otlp.protocol", cfg.Protocol),
+		zap.Strings("otlp.headers", redactedHeaders(cfg.Headers)),
 	)
 	return out, nil
 }
 
+// checkEnvOverrides reports OTEL_* variables, applied by applyConfigDefaults
+// without Config's validation, whose values telemetry does not support.
+func checkEnvOverrides() error {
+	var errs []error
+	if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")); v != "" && v != ProtocolGRPC && v != ProtocolHTTPProtobuf {
+		errs = append(errs, fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL: unsupported protocol %q (want %q or %q)", v, ProtocolGRPC, ProtocolHTTPProtobuf))
+	}
+	if v := strings.TrimSpace(os.Getenv("OTEL_PROPAGATORS")); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); !slices.Contains(propagatorNames, name) {
+				errs = append(errs, fmt.Errorf("OTEL_PROPAGATORS: unsupported propagator %q (want one of %s)", name, strings.Join(propagatorNames, ", ")))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // applyConfigDefaults populates the Config struct with values from environment
 // variables, the meta package, and hardcoded defaults.
 func applyConfigDefaults(cfg *Config) {
@@ -188,6 +420,24 @@ func applyConfigDefaults(cfg *Config) {
 	if envEndpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); envEndpoint != "" {
 		cfg.OTLPEndpoint = envEndpoint
 	}
+	if envEndpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")); envEndpoint != "" {
+		cfg.TraceEndpoint = envEndpoint
+	}
+	if envEndpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")); envEndpoint != "" {
+		cfg.MetricEndpoint = envEndpoint
+	}
+	if envProtocol := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")); envProtocol != "" {
+		cfg.Protocol = envProtocol
+	}
+	if envPropagators := strings.TrimSpace(os.Getenv("OTEL_PROPAGATORS")); envPropagators != "" {
+		cfg.Propagators = nil
+		for _, name := range strings.Split(envPropagators, ",") {
+			cfg.Propagators = append(cfg.Propagators, strings.TrimSpace(name))
+		}
+	}
+	if envHeaders := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")); envHeaders != "" {
+		cfg.Headers = mergeHeaders(cfg.Headers, parseHeaders(envHeaders))
+	}
 	if envServiceName := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")); envServiceName != "" {
 		cfg.ServiceName = envServiceName
 	}
@@ -205,36 +455,71 @@ func applyConfigDefaults(cfg *Config) {
 		cfg.ServiceVersion = runtimeinfo.Version
 	}
 	if cfg.Environment == "" {
-		cfg.Environment = coalesceEnv("ENV", "APP_ENV", "GO_ENV")
-		if cfg.Environment == "" {
-			cfg.Environment = "dev"
-		}
+		cfg.Environment = configkit.Environment()
+	}
+
+	// Sample everything outside production unless a sampler or a rate is
+	// configured.
+	if cfg.TraceSampler == "" {
+		cfg.TraceSampler = defaultSampler(cfg.Environment, cfg.TraceSampleRate)
 	}
 
 	// Lowest precedence: hardcoded defaults
 	if cfg.TraceSampleRate <= 0 {
 		cfg.TraceSampleRate = 1.0
 	}
+	if cfg.Exporter == "" {
+		cfg.Exporter = ExporterOTLP
+	}
+	if cfg.MetricsExporter == "" {
+		cfg.MetricsExporter = MetricsExporterOTLP
+	}
+	if cfg.Protocol == "" {
+		cfg.Protocol = ProtocolGRPC
+	}
 	if cfg.ExportInterval <= 0 {
 		cfg.ExportInterval = 30 * time.Second
 	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = defaultShutdownTimeout
+	}
 
 	// Set defaults for boolean pointers if they are nil
 	setDefaultBool(&cfg.Disabled, false)
-	enabledByEndpoint := cfg.OTLPEndpoint != "" && !*cfg.Disabled
-	setDefaultBool(&cfg.TracingEnabled, enabledByEndpoint)
-	setDefaultBool(&cfg.MetricsEnabled, enabledByEndpoint)
+	setDefaultBool(&cfg.SetGlobals, true)
+	setDefaultBool(&cfg.TracingEnabled, exporting(*cfg, traceEndpoint(*cfg)) && !*cfg.Disabled)
+	// Prometheus is scraped, so it needs no exporter.
+	scraped := cfg.MetricsExporter == MetricsExporterPrometheus
+	setDefaultBool(&cfg.MetricsEnabled, (exporting(*cfg, metricEndpoint(*cfg)) || scraped) && !*cfg.Disabled)
+	setDefaultBool(&cfg.RuntimeMetrics, *cfg.MetricsEnabled)
 
 	// Final check: if the entire SDK is disabled, tracing and metrics must also be disabled.
 	if *cfg.Disabled {
 		disabledState := false
 		cfg.TracingEnabled = &disabledState
 		cfg.MetricsEnabled = &disabledState
+		cfg.RuntimeMetrics = &disabledState
+	}
+}
+
+// defaultSampler returns the sampler used when trace_sampler is unset:
+// parent_ratio when a rate is set, else always_on for development and staging
+// environments and parent_ratio otherwise.
+func defaultSampler(env string, rate float64) string {
+	if rate > 0 {
+		return "parent_ratio"
+	}
+	switch strings.ToLower(env) {
+	case "dev", "development", "local", "test", "staging", "stage":
+		return "always_on"
+	default:
+		return "parent_ratio"
 	}
 }
 
 // buildResource creates the OTEL resource by merging attributes from the default
-// resource, configuration, and runtime metadata package.
+// resource, configuration, runtime metadata package, and ResourceGroup, each
+// overriding the ones before.
 func buildResource(cfg Config) (*sdkresource.Resource, error) {
 	// Standard attributes
 	attrs := []attribute.KeyValue{
@@ -259,6 +544,9 @@ func buildResource(cfg Config) (*sdkresource.Resource, error) {
 	for k, v := range cfg.ResourceAttributes {
 		extraConfigAttrs = append(extraConfigAttrs, attribute.String(k, v))
 	}
+	for k, v := range cfg.configValues {
+		extraConfigAttrs = append(extraConfigAttrs, attribute.String("config."+k, v))
+	}
 	extraAttrs := sdkresource.NewWithAttributes(semconv.SchemaURL, extraConfigAttrs...)
 
 	// Merge all resource sources.
@@ -270,14 +558,24 @@ func buildResource(cfg Config) (*sdkresource.Resource, error) {
 	if err != nil {
 		return nil, err
 	}
-	return sdkresource.Merge(res, extraAttrs)
+	res, err = sdkresource.Merge(res, extraAttrs)
+	if err != nil {
+		return nil, err
+	}
+	// Schemaless, so group attributes merge under any schema URL.
+	return sdkresource.Merge(res, sdkresource.NewSchemaless(cfg.groupAttrs...))
 }
 
+// defaultShutdownTimeout applies when Config.ShutdownTimeout is unset.
+const defaultShutdownTimeout = 15 * time.Second
+
 type shutdownDeps struct {
 	fx.In
 
+	Cfg            *Config `optional:"true"`
 	TracerProvider *sdktrace.TracerProvider
 	MeterProvider  *sdkmetric.MeterProvider
+	SharedConn     *grpc.ClientConn `name:"telemetry.otlp_conn" optional:"true"`
 	Logger         *zap.Logger
 	LC             fx.Lifecycle
 }
@@ -287,22 +585,45 @@ type shutdownDeps struct {
 func registerShutdown(params shutdownDeps) {
 	params.LC.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
-			params.Logger.Info("shutting down telemetry providers")
-			// Create a new context for shutdown to avoid premature cancellation from Fx's OnStop context.
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			timeout := defaultShutdownTimeout
+			if params.Cfg != nil && params.Cfg.ShutdownTimeout > 0 {
+				timeout = params.Cfg.ShutdownTimeout
+			}
+			// Deriving from the stop context keeps whichever deadline is
+			// sooner: ours or the app's overall stop budget.
+			shutdownCtx, cancel := context.WithTimeout(ctx, timeout)
 			defer cancel()
+			deadline, _ := shutdownCtx.Deadline()
+			params.Logger.Info("shutting down telemetry providers", zap.Duration("budget", time.Until(deadline)))
 
+			start := time.Now()
 			// Attempt both shutdowns and join errors to ensure both are attempted.
-			return errors.Join(
+			err := errors.Join(
 				shutdownMeter(shutdownCtx, params.MeterProvider, params.Logger),
 				shutdownTracer(shutdownCtx, params.TracerProvider, params.Logger),
 			)
+			// The exporters no longer use the shared connection once both
+			// providers are down.
+			if params.SharedConn != nil {
+				err = errors.Join(err, params.SharedConn.Close())
+			}
+			// The SDK does not report counts; a blown deadline is the signal
+			// that buffered spans or metrics were dropped.
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				params.Logger.Warn("telemetry flush cut short; pending data may be dropped",
+					zap.Duration("elapsed", time.Since(start)),
+				)
+			} else if err == nil {
+				params.Logger.Info("telemetry flushed", zap.Duration("elapsed", time.Since(start)))
+			}
+			return err
 		},
 	})
 }
 
 // buildTracerProvider creates a new trace provider with a configured sampler and exporter.
-func buildTracerProvider(ctx context.Context, cfg Config, res *sdkresource.Resource) (*sdktrace.TracerProvider, error) {
+// A non-nil conn is used by the exporter instead of dialing the endpoint.
+func buildTracerProvider(ctx context.Context, cfg Config, res *sdkresource.Resource, conn *grpc.ClientConn) (*sdktrace.TracerProvider, error) {
 	var sampler sdktrace.Sampler
 	switch cfg.TraceSampler {
 	case "always_on":
@@ -314,50 +635,56 @@ func buildTracerProvider(ctx context.Context, cfg Config, res *sdkresource.Resou
 	default:
 		return nil, fmt.Errorf("unknown trace sampler: %q", cfg.TraceSampler)
 	}
-
-	if *cfg.TracingEnabled && cfg.OTLPEndpoint != "" {
-		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlptracegrpc.WithInsecure())
+	if len(cfg.SamplingRules) > 0 {
+		var err error
+		if sampler, err = newRuleSampler(cfg.SamplingRules, sampler); err != nil {
+			return nil, err
 		}
-		exp, err := otlptracegrpc.New(ctx, opts...)
-		if err != nil {
-			return nil, fmt.Errorf("otlp trace exporter: %w", err)
-		}
-		return sdktrace.NewTracerProvider(
-			sdktrace.WithBatcher(exp),
-			sdktrace.WithResource(res),
-			sdktrace.WithSampler(sampler),
-		), nil
 	}
 
-	// Return a provider with no exporter if tracing is disabled or no endpoint is set.
-	return sdktrace.NewTracerProvider(
+	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sampler),
-	), nil
+	}
+	if cfg.SpanBuildInfo {
+		opts = append(opts, sdktrace.WithSpanProcessor(newBuildInfoProcessor(cfg)))
+	}
+
+	// Only attach an exporter if tracing is enabled and one is configured.
+	if *cfg.TracingEnabled && exporting(cfg, traceEndpoint(cfg)) {
+		exp, err := newTraceExporter(ctx, cfg, conn)
+		if err != nil {
+			return nil, err
+		}
+		var bsp sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(exp)
+		if len(cfg.ExcludeSpans) > 0 {
+			bsp = newExcludeProcessor(cfg.ExcludeSpans, bsp)
+		}
+		opts = append(opts, sdktrace.WithSpanProcessor(bsp))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
 }
 
 // buildMeterProvider creates a new meter provider with a configured exporter.
-func buildMeterProvider(ctx context.Context, cfg Config, res *sdkresource.Resource) (*sdkmetric.MeterProvider, error) {
-	if *cfg.MetricsEnabled && cfg.OTLPEndpoint != "" {
-		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(cfg.OTLPEndpoint)}
-		if cfg.Insecure {
-			opts = append(opts, otlpmetricgrpc.WithInsecure())
-		}
-		exp, err := otlpmetricgrpc.New(ctx, opts...)
+// Extra readers are attached whether or not an exporter is configured. A
+// non-nil conn is used by the exporter instead of dialing the endpoint.
+func buildMeterProvider(ctx context.Context, cfg Config, res *sdkresource.Resource, conn *grpc.ClientConn, extra ...sdkmetric.Reader) (*sdkmetric.MeterProvider, error) {
+	mpOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, r := range extra {
+		mpOpts = append(mpOpts, sdkmetric.WithReader(r))
+	}
+	if *cfg.MetricsEnabled && cfg.MetricsExporter != MetricsExporterPrometheus && exporting(cfg, metricEndpoint(cfg)) {
+		exp, err := newMetricExporter(ctx, cfg, conn)
 		if err != nil {
-			return nil, fmt.Errorf("otlp metric exporter: %w", err)
+			return nil, err
 		}
 		reader := sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(cfg.ExportInterval))
-		return sdkmetric.NewMeterProvider(
-			sdkmetric.WithReader(reader),
-			sdkmetric.WithResource(res),
-		), nil
+		mpOpts = append(mpOpts, sdkmetric.WithReader(reader))
 	}
 
-	// Return a provider with no exporter if metrics are disabled or no endpoint is set.
-	return sdkmetric.NewMeterProvider(sdkmetric.WithResource(res)), nil
+	// Without an exporter the provider only feeds the extra readers, if any.
+	return sdkmetric.NewMeterProvider(mpOpts...), nil
 }
 
 // shutdownTracer gracefully stops the tracer provider.
@@ -384,16 +711,6 @@ func shutdownMeter(ctx context.Context, mp *sdkmetric.MeterProvider, log *zap.Lo
 	return nil
 }
 
-// coalesceEnv returns the value of the first non-empty environment variable.
-func coalesceEnv(keys ...string) string {
-	for _, k := range keys {
-		if v := strings.TrimSpace(os.Getenv(k)); v != "" {
-			return v
-		}
-	}
-	return ""
-}
-
 // setDefaultBool sets a bool pointer to the default value if it is nil.
 func setDefaultBool(b **bool, defaultValue bool) {
 	if *b == nil {
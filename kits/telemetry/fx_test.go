@@ -5,17 +5,23 @@ import (
 	"testing"
 	"time"
 
+	"github.com/froppa/stackkit/kits/configkit"
 	info "github.com/froppa/stackkit/kits/runtimeinfo"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
 	fxtest "go.uber.org/fx/fxtest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/connectivity"
 )
 
 func TestInstallGlobals(t *testing.T) {
@@ -78,6 +84,86 @@ func TestNewProvidersDisabled(t *testing.T) {
 	}
 }
 
+func TestInstallGlobalsSkippedWhenSetGlobalsFalse(t *testing.T) {
+	prevTracer := otel.GetTracerProvider()
+	prevMeter := otel.GetMeterProvider()
+
+	off := false
+	installGlobals(globalDeps{
+		TracerProvider: sdktrace.NewTracerProvider(),
+		MeterProvider:  sdkmetric.NewMeterProvider(),
+		Cfg:            &Config{SetGlobals: &off},
+	})
+
+	require.Equal(t, prevTracer, otel.GetTracerProvider())
+	require.Equal(t, prevMeter, otel.GetMeterProvider())
+}
+
+func TestModuleDisabledProvidesNoopTracerAndMeter(t *testing.T) {
+	prevTracer, prevMeter := otel.GetTracerProvider(), otel.GetMeterProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTracer)
+		otel.SetMeterProvider(prevMeter)
+	})
+
+	var tracer trace.Tracer
+	var meter metric.Meter
+	app := fxtest.New(t,
+		fx.Provide(context.Background),
+		fx.Provide(zap.NewNop),
+		configkit.Module(configkit.WithEmbeddedBytes([]byte("telemetry:\n  disabled: true\n"))),
+		Module(),
+		fx.Populate(&tracer, &meter),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	require.NotNil(t, tracer)
+	require.NotNil(t, meter)
+	_, span := tracer.Start(context.Background(), "op")
+	require.False(t, span.SpanContext().IsSampled())
+	span.End()
+	counter, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 1)
+}
+
+func TestNoop(t *testing.T) {
+	var tracer trace.Tracer
+	var meter metric.Meter
+	fxtest.New(t, Noop(), fx.Populate(&tracer, &meter)).RequireStart().RequireStop()
+
+	_, span := tracer.Start(context.Background(), "op")
+	require.False(t, span.IsRecording())
+	span.End()
+	_, err := meter.Float64Histogram("latency")
+	require.NoError(t, err)
+}
+
+func TestNewProvidersBestEffort(t *testing.T) {
+	// An unknown sampler (normally rejected by validation) makes tracing fail.
+	cfg := func(bestEffort bool) *Config {
+		return &Config{ServiceName: "svc", TraceSampler: "bogus", BestEffort: bestEffort}
+	}
+
+	_, err := NewProviders(context.Background(), cfg(false), zap.NewNop())
+	require.ErrorContains(t, err, "unknown trace sampler")
+
+	core, logs := observer.New(zapcore.ErrorLevel)
+	out, err := NewProviders(context.Background(), cfg(true), zap.New(core))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = out.TracerProvider.Shutdown(context.Background())
+		_ = out.MeterProvider.Shutdown(context.Background())
+	})
+	require.Equal(t, 1, logs.FilterMessage("telemetry: tracing unavailable; continuing without it").Len())
+
+	_, span := out.Tracer.Start(context.Background(), "op")
+	require.False(t, span.SpanContext().IsSampled())
+	span.End()
+	require.NotNil(t, out.Meter)
+}
+
 func TestNewProvidersWarnsWhenNoEndpoint(t *testing.T) {
 	core, logs := observer.New(zapcore.WarnLevel)
 	logger := zap.New(core)
@@ -156,6 +242,36 @@ func TestApplyConfigDefaults(t *testing.T) {
 	if cfg.ExportInterval != 30*time.Second {
 		t.Fatalf("expected default export interval, got %s", cfg.ExportInterval)
 	}
+	if cfg.ShutdownTimeout != 15*time.Second {
+		t.Fatalf("expected default shutdown timeout, got %s", cfg.ShutdownTimeout)
+	}
+	if cfg.TraceSampler != "parent_ratio" {
+		t.Fatalf("expected parent_ratio sampler in prod, got %q", cfg.TraceSampler)
+	}
+}
+
+func TestApplyConfigDefaults_SamplerByEnvironment(t *testing.T) {
+	for env, want := range map[string]string{
+		"dev":        "always_on",
+		"staging":    "always_on",
+		"production": "parent_ratio",
+		"canary":     "parent_ratio",
+	} {
+		cfg := &Config{Environment: env}
+		applyConfigDefaults(cfg)
+		require.Equal(t, want, cfg.TraceSampler, env)
+	}
+
+	// A configured rate selects parent_ratio, even in dev.
+	cfg := &Config{Environment: "dev", TraceSampleRate: 0.1}
+	applyConfigDefaults(cfg)
+	require.Equal(t, "parent_ratio", cfg.TraceSampler)
+	require.Equal(t, 0.1, cfg.TraceSampleRate)
+
+	// An explicit sampler always wins.
+	cfg = &Config{Environment: "dev", TraceSampler: "always_off"}
+	applyConfigDefaults(cfg)
+	require.Equal(t, "always_off", cfg.TraceSampler)
 }
 
 func TestBuildResourceIncludesAttributes(t *testing.T) {
@@ -200,17 +316,19 @@ func TestBuildResourceIncludesAttributes(t *testing.T) {
 	}
 }
 
-func TestCoalesceEnv(t *testing.T) {
-	t.Setenv("FIRST", "")
-	t.Setenv("SECOND", "value")
-	t.Setenv("THIRD", "ignored")
+func TestApplyConfigDefaults_EnvironmentFromConfigkit(t *testing.T) {
+	t.Setenv("ENV", "")
+	t.Setenv("APP_ENV", "qa")
+	t.Setenv("GO_ENV", "ignored")
+	cfg := &Config{}
+	applyConfigDefaults(cfg)
+	require.Equal(t, "qa", cfg.Environment)
 
-	if got := coalesceEnv("FIRST", "SECOND", "THIRD"); got != "value" {
-		t.Fatalf("expected SECOND, got %s", got)
-	}
-	if got := coalesceEnv("MISSING"); got != "" {
-		t.Fatalf("expected empty string, got %s", got)
-	}
+	t.Setenv("APP_ENV", "")
+	t.Setenv("GO_ENV", "")
+	cfg = &Config{}
+	applyConfigDefaults(cfg)
+	require.Equal(t, "dev", cfg.Environment)
 }
 
 func TestSetDefaultBool(t *testing.T) {
@@ -249,6 +367,101 @@ func TestRegisterShutdown(t *testing.T) {
 	}
 }
 
+func TestNewProvidersSharedConnection(t *testing.T) {
+	cfg := &Config{OTLPEndpoint: "localhost:4317", Insecure: true}
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if out.SharedConn != nil {
+		t.Fatalf("expected no shared connection by default")
+	}
+	_ = out.TracerProvider.Shutdown(context.Background())
+
+	cfg = &Config{OTLPEndpoint: "localhost:4317", Insecure: true, SharedConnection: true}
+	out, err = NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if out.SharedConn == nil {
+		t.Fatalf("expected a shared connection")
+	}
+
+	lc := fxtest.NewLifecycle(t)
+	registerShutdown(shutdownDeps{
+		TracerProvider: out.TracerProvider,
+		MeterProvider:  out.MeterProvider,
+		SharedConn:     out.SharedConn,
+		Logger:         zap.NewNop(),
+		LC:             lc,
+	})
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatalf("start lifecycle: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_ = lc.Stop(ctx) // the final metric export has no collector to reach
+	if state := out.SharedConn.GetState(); state != connectivity.Shutdown {
+		t.Fatalf("expected shared connection closed on stop, got %s", state)
+	}
+}
+
+func TestNewProvidersSharedConnectionClosedOnError(t *testing.T) {
+	cfg := &Config{OTLPEndpoint: "localhost:4317", Insecure: true, SharedConnection: true, TraceSampler: "bogus"}
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err == nil {
+		t.Fatalf("expected an error for the unknown sampler")
+	}
+	if out.SharedConn == nil {
+		t.Fatalf("expected a shared connection to have been dialed")
+	}
+	if state := out.SharedConn.GetState(); state != connectivity.Shutdown {
+		t.Fatalf("expected shared connection closed on error, got %s", state)
+	}
+}
+
+// stuckExporter never finishes shutting down until its context ends.
+type stuckExporter struct{}
+
+func (stuckExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return nil }
+func (stuckExporter) Shutdown(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRegisterShutdownRespectsStopDeadline(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	lc := fxtest.NewLifecycle(t)
+	registerShutdown(shutdownDeps{
+		Cfg:            &Config{ShutdownTimeout: 10 * time.Second},
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithBatcher(stuckExporter{})),
+		MeterProvider:  sdkmetric.NewMeterProvider(),
+		Logger:         zap.New(core),
+		LC:             lc,
+	})
+	if err := lc.Start(context.Background()); err != nil {
+		t.Fatalf("start lifecycle: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := lc.Stop(ctx); err == nil {
+		t.Fatalf("expected stop to report the blown deadline")
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Fatalf("shutdown ignored the stop deadline: took %s", took)
+	}
+
+	budget := logs.FilterMessage("shutting down telemetry providers").All()[0].ContextMap()["budget"].(time.Duration)
+	if budget > 50*time.Millisecond {
+		t.Fatalf("expected budget capped by stop context, got %s", budget)
+	}
+	if logs.FilterMessage("telemetry flush cut short; pending data may be dropped").Len() != 1 {
+		t.Fatalf("expected dropped-data warning")
+	}
+}
+
 func TestBuildTracerProviderInvalidSampler(t *testing.T) {
 	tracing := true
 	cfg := Config{
@@ -257,7 +470,7 @@ func TestBuildTracerProviderInvalidSampler(t *testing.T) {
 		TraceSampleRate: 1,
 	}
 	res := sdkresource.NewSchemaless()
-	if _, err := buildTracerProvider(context.Background(), cfg, res); err == nil {
+	if _, err := buildTracerProvider(context.Background(), cfg, res, nil); err == nil {
 		t.Fatalf("expected sampler error")
 	}
 }
@@ -271,7 +484,7 @@ func TestBuildTracerProviderWithEndpoint(t *testing.T) {
 		Insecure:        true,
 	}
 	res := sdkresource.NewSchemaless()
-	tp, err := buildTracerProvider(context.Background(), cfg, res)
+	tp, err := buildTracerProvider(context.Background(), cfg, res, nil)
 	if err != nil {
 		t.Fatalf("unexpected tracer provider error: %v", err)
 	}
@@ -353,3 +566,33 @@ func boolAttrEquals(attrs []attribute.KeyValue, key attribute.Key, expected bool
 	}
 	return false
 }
+
+func TestModuleAddsConfigAttributesToResource(t *testing.T) {
+	yml := `
+telemetry:
+  set_globals: false
+  config_attributes: [telemetry.trace_sampler, db.password, telemetry.missing]
+  trace_sampler: always_on
+db:
+  password: hunter2
+`
+	var tp *sdktrace.TracerProvider
+	app := fxtest.New(t,
+		fx.Provide(context.Background),
+		fx.Provide(zap.NewNop),
+		configkit.Module(configkit.WithEmbeddedBytes([]byte(yml))),
+		Module(),
+		fx.Populate(&tp),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	attrs := span.(sdktrace.ReadOnlySpan).Resource().Attributes()
+	require.True(t, attrEquals(attrs, "config.telemetry.trace_sampler", "always_on"), "%v", attrs)
+	require.True(t, attrEquals(attrs, "config.db.password", "***"), "%v", attrs)
+	for _, kv := range attrs {
+		require.NotEqual(t, attribute.Key("config.telemetry.missing"), kv.Key)
+	}
+}
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// MetricsDump prints on-demand snapshots of the current metric values. It
+// reads through its own manual reader, so periodic OTLP export is unaffected.
+type MetricsDump struct {
+	signal string
+	reader *sdkmetric.ManualReader
+	out    io.Writer
+}
+
+func newMetricsDump(sig string) *MetricsDump {
+	return &MetricsDump{signal: sig, reader: sdkmetric.NewManualReader(), out: os.Stdout}
+}
+
+// Dump writes the current metric values to w as indented JSON. Only the
+// dump's own reader is collected; other readers, such as the OTLP exporter,
+// are not flushed.
+func (d *MetricsDump) Dump(ctx context.Context, w io.Writer) error {
+	var rm metricdata.ResourceMetrics
+	if err := d.reader.Collect(ctx, &rm); err != nil {
+		return fmt.Errorf("metrics dump: collect: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rm)
+}
+
+type metricsDumpDeps struct {
+	fx.In
+
+	Dump   *MetricsDump `optional:"true"`
+	Logger *zap.Logger
+	LC     fx.Lifecycle
+}
+
+// registerMetricsDump prints a snapshot to stdout each time the configured
+// signal arrives, between application start and stop.
+func registerMetricsDump(d metricsDumpDeps) {
+	if d.Dump == nil {
+		return
+	}
+	sig, ok := dumpSignals[d.Dump.signal]
+	if !ok {
+		d.Logger.Warn("metrics dump signal not supported on this platform", zap.String("signal", d.Dump.signal))
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	d.LC.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			signal.Notify(ch, sig)
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					case <-ch:
+						ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+						if err := d.Dump.Dump(ctx, d.Dump.out); err != nil {
+							d.Logger.Warn("metrics dump failed", zap.Error(err))
+						}
+						cancel()
+					}
+				}
+			}()
+			d.Logger.Info("metrics dump enabled", zap.String("signal", d.Dump.signal))
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			signal.Stop(ch)
+			close(done)
+			return nil
+		},
+	})
+}
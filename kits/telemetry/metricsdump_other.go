@@ -0,0 +1,8 @@
+//go:build !unix
+
+package telemetry
+
+import "os"
+
+// SIGUSR1/SIGUSR2 do not exist here; a configured dump signal is ignored.
+var dumpSignals = map[string]os.Signal{}
@@ -0,0 +1,60 @@
+//go:build unix
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	fxtest "go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+type chanWriter chan string
+
+func (c chanWriter) Write(p []byte) (int, error) {
+	c <- string(p)
+	return len(p), nil
+}
+
+func TestMetricsDump(t *testing.T) {
+	out, err := NewProviders(context.Background(), &Config{MetricsDumpSignal: "SIGUSR2"}, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, out.MetricsDump)
+	t.Cleanup(func() { _ = out.MeterProvider.Shutdown(context.Background()) })
+
+	counter, err := out.Meter.Int64Counter("jobs.processed")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3)
+
+	var buf bytes.Buffer
+	require.NoError(t, out.MetricsDump.Dump(context.Background(), &buf))
+	require.Contains(t, buf.String(), `"Name": "jobs.processed"`)
+	require.Contains(t, buf.String(), `"Value": 3`)
+
+	// The configured signal triggers the same dump.
+	w := make(chanWriter, 1)
+	out.MetricsDump.out = w
+	lc := fxtest.NewLifecycle(t)
+	registerMetricsDump(metricsDumpDeps{Dump: out.MetricsDump, Logger: zap.NewNop(), LC: lc})
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	select {
+	case s := <-w:
+		require.Contains(t, s, "jobs.processed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("no metrics dump after signal")
+	}
+}
+
+func TestMetricsDumpOffByDefault(t *testing.T) {
+	out, err := NewProviders(context.Background(), &Config{}, zap.NewNop())
+	require.NoError(t, err)
+	require.Nil(t, out.MetricsDump)
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package telemetry
+
+import (
+	"os"
+	"syscall"
+)
+
+var dumpSignals = map[string]os.Signal{
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
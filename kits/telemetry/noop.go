@@ -0,0 +1,20 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+)
+
+// Noop provides a no-op trace.Tracer and metric.Meter without any config,
+// exporters, or globals. Use it in place of Module in tests and in apps that
+// leave telemetry out, so constructors can keep injecting Tracer and Meter
+// unconditionally instead of marking them optional and checking for nil.
+func Noop() fx.Option {
+	return fx.Provide(
+		func() trace.Tracer { return tracenoop.NewTracerProvider().Tracer("") },
+		func() metric.Meter { return metricnoop.NewMeterProvider().Meter("") },
+	)
+}
@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/froppa/stackkit/kits/httpkit"
+	"github.com/froppa/stackkit/kits/runtimeinfo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Metrics exporters accepted by Config.MetricsExporter.
+const (
+	MetricsExporterOTLP       = "otlp"
+	MetricsExporterPrometheus = "prometheus"
+)
+
+// newPrometheusReader returns a reader that collects the meter provider's
+// metrics into a dedicated Prometheus registry, and the handler, for
+// MetricsPath, that serves that registry.
+func newPrometheusReader(cfg Config) (sdkmetric.Reader, httpkit.Handler, error) {
+	reg := prometheus.NewRegistry()
+	opts := []otelprom.Option{otelprom.WithRegisterer(reg)}
+	if cfg.PrometheusRuntimeLabels {
+		opts = append(opts, otelprom.WithResourceAsConstantLabels(runtimeLabels()))
+	}
+	exp, err := otelprom.New(opts...)
+	if err != nil {
+		return nil, httpkit.Handler{}, fmt.Errorf("prometheus exporter: %w", err)
+	}
+	var h http.Handler = promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+	return exp, httpkit.Handler{Pattern: "GET " + MetricsPath, Handler: h}, nil
+}
+
+// runtimeLabels selects the resource attributes contributed by runtimeinfo.
+func runtimeLabels() attribute.Filter {
+	keys := make(map[attribute.Key]struct{})
+	for _, kv := range runtimeinfo.OTELAttributes() {
+		keys[kv.Key] = struct{}{}
+	}
+	return func(kv attribute.KeyValue) bool {
+		_, ok := keys[kv.Key]
+		return ok
+	}
+}
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	info "github.com/froppa/stackkit/kits/runtimeinfo"
+	"go.uber.org/zap"
+)
+
+func TestNewProvidersPrometheus(t *testing.T) {
+	orig := snapshotInfo()
+	defer restoreInfo(orig)
+	info.Version = "1.2.3"
+	info.Commit = "abc123"
+
+	// No endpoint: scraping alone enables metrics.
+	cfg := &Config{MetricsExporter: MetricsExporterPrometheus, PrometheusRuntimeLabels: true}
+	out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	defer func() { _ = out.MeterProvider.Shutdown(context.Background()) }()
+	if len(out.MetricsHandlers) != 1 || out.MetricsHandlers[0].Pattern != "GET "+MetricsPath {
+		t.Fatalf("expected one handler for %s, got %+v", MetricsPath, out.MetricsHandlers)
+	}
+
+	counter, err := out.Meter.Int64Counter("jobs")
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	counter.Add(context.Background(), 3)
+
+	rec := httptest.NewRecorder()
+	out.MetricsHandlers[0].Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	body := rec.Body.String()
+	var series string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "jobs_total{") {
+			series = line
+		}
+	}
+	if series == "" || !strings.HasSuffix(series, " 3") {
+		t.Fatalf("expected jobs_total 3, got:\n%s", body)
+	}
+	for _, label := range []string{`service_version="1.2.3"`, `vcs_revision="abc123"`} {
+		if !strings.Contains(series, label) {
+			t.Fatalf("expected constant label %s on %q", label, series)
+		}
+	}
+}
+
+func TestNewProvidersNoMetricsHandlersByDefault(t *testing.T) {
+	out, err := NewProviders(context.Background(), &Config{}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewProviders: %v", err)
+	}
+	if len(out.MetricsHandlers) != 0 {
+		t.Fatalf("expected no metrics handlers, got %+v", out.MetricsHandlers)
+	}
+}
+
+func TestNewProvidersRuntimeMetrics(t *testing.T) {
+	scrape := func(cfg *Config) string {
+		t.Helper()
+		out, err := NewProviders(context.Background(), cfg, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewProviders: %v", err)
+		}
+		defer func() { _ = out.MeterProvider.Shutdown(context.Background()) }()
+		rec := httptest.NewRecorder()
+		out.MetricsHandlers[0].Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+		return rec.Body.String()
+	}
+
+	// On by default once metrics are enabled.
+	if body := scrape(&Config{MetricsExporter: MetricsExporterPrometheus}); !strings.Contains(body, "go_goroutine_count") {
+		t.Fatalf("expected runtime metrics, got:\n%s", body)
+	}
+
+	off := false
+	if body := scrape(&Config{MetricsExporter: MetricsExporterPrometheus, RuntimeMetrics: &off}); strings.Contains(body, "go_goroutine_count") {
+		t.Fatalf("expected no runtime metrics, got:\n%s", body)
+	}
+}
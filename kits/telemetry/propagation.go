@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// defaultPropagators applies when Config.Propagators is empty.
+var defaultPropagators = []string{"tracecontext", "baggage"}
+
+// propagatorNames lists the names newPropagator accepts. "none" disables
+// propagation.
+var propagatorNames = []string{"tracecontext", "baggage", "b3", "b3multi", "jaeger", "none"}
+
+// newPropagator returns the composite of the named propagators, in order, or
+// one that propagates nothing when the names include "none". Repeated names
+// are skipped; names come validated from Config or from OTEL_PROPAGATORS.
+func newPropagator(names []string) propagation.TextMapPropagator {
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+	if slices.Contains(names, "none") {
+		return propagation.NewCompositeTextMapPropagator()
+	}
+	seen := make(map[string]bool, len(names))
+	var props []propagation.TextMapPropagator
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch name {
+		case "tracecontext":
+			props = append(props, propagation.TraceContext{})
+		case "baggage":
+			props = append(props, propagation.Baggage{})
+		case "b3":
+			props = append(props, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			props = append(props, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "jaeger":
+			props = append(props, jaeger.Jaeger{})
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(props...)
+}
+
+// MapCarrier adapts a map[string]string of message headers to the
+// propagation.TextMapCarrier interface.
+type MapCarrier = propagation.MapCarrier
+
+// ExtractContext returns ctx enriched with the trace context and baggage found
+// in carrier, using prop (Module provides it; nil means the otel global).
+// Use it to continue a trace from an incoming message in non-HTTP transports
+// (Kafka, NATS, ...).
+func ExtractContext(ctx context.Context, prop propagation.TextMapPropagator, carrier propagation.TextMapCarrier) context.Context {
+	return orGlobal(prop).Extract(ctx, carrier)
+}
+
+// InjectContext writes the trace context and baggage of ctx into carrier,
+// using prop (nil means the otel global), so the receiver can continue the
+// trace.
+func InjectContext(ctx context.Context, prop propagation.TextMapPropagator, carrier propagation.TextMapCarrier) {
+	orGlobal(prop).Inject(ctx, carrier)
+}
+
+func orGlobal(prop propagation.TextMapPropagator) propagation.TextMapPropagator {
+	if prop == nil {
+		return otel.GetTextMapPropagator()
+	}
+	return prop
+}
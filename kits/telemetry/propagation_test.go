@@ -0,0 +1,157 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func TestInjectExtractContextRoundTrip(t *testing.T) {
+	prevProp := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(prevProp)
+	installGlobals(globalDeps{})
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "produce")
+	defer span.End()
+	member, err := baggage.NewMember("tenant", "acme")
+	if err != nil {
+		t.Fatalf("baggage member: %v", err)
+	}
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatalf("baggage: %v", err)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+
+	headers := MapCarrier{}
+	InjectContext(ctx, nil, headers)
+	if headers["traceparent"] == "" {
+		t.Fatalf("expected traceparent header, got %v", headers)
+	}
+
+	got := ExtractContext(context.Background(), nil, headers)
+	sc := trace.SpanContextFromContext(got)
+	if sc.TraceID() != span.SpanContext().TraceID() {
+		t.Fatalf("expected trace id %s, got %s", span.SpanContext().TraceID(), sc.TraceID())
+	}
+	if !sc.IsRemote() {
+		t.Fatalf("expected remote span context")
+	}
+	if v := baggage.FromContext(got).Member("tenant").Value(); v != "acme" {
+		t.Fatalf("expected baggage tenant=acme, got %q", v)
+	}
+}
+
+func TestInstallGlobalsConfiguredPropagators(t *testing.T) {
+	prevProp := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(prevProp)
+	installGlobals(globalDeps{Cfg: &Config{Propagators: []string{"b3", "tracecontext"}}})
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "call")
+	defer span.End()
+
+	carrier := MapCarrier{}
+	InjectContext(ctx, nil, carrier)
+	if carrier["b3"] == "" || carrier["traceparent"] == "" {
+		t.Fatalf("expected b3 and traceparent headers, got %v", carrier)
+	}
+	if _, ok := carrier["baggage"]; ok {
+		t.Fatalf("expected no baggage propagator, got %v", carrier)
+	}
+
+	// A legacy caller sending only B3 continues the same trace.
+	got := trace.SpanContextFromContext(ExtractContext(context.Background(), nil, MapCarrier{"b3": carrier["b3"]}))
+	if got.TraceID() != span.SpanContext().TraceID() {
+		t.Fatalf("expected trace %s from b3, got %s", span.SpanContext().TraceID(), got.TraceID())
+	}
+}
+
+func TestModuleWithoutGlobalsProvidesPropagator(t *testing.T) {
+	prevProp := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(prevProp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	var prop propagation.TextMapPropagator
+	app := fxtest.New(t,
+		fx.Provide(context.Background),
+		fx.Provide(zap.NewNop),
+		configkit.Module(configkit.WithEmbeddedBytes([]byte("telemetry:\n  disabled: true\n  set_globals: false\n  propagators: [b3]\n"))),
+		Module(),
+		fx.Populate(&prop),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if len(otel.GetTextMapPropagator().Fields()) != 0 {
+		t.Fatalf("expected the global propagator to be left alone")
+	}
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "produce")
+	defer span.End()
+
+	carrier := MapCarrier{}
+	InjectContext(ctx, prop, carrier)
+	if carrier["b3"] == "" {
+		t.Fatalf("expected a b3 header, got %v", carrier)
+	}
+	got := trace.SpanContextFromContext(ExtractContext(context.Background(), prop, carrier))
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("expected span context %v to round-trip, got %v", span.SpanContext(), got)
+	}
+}
+
+func TestNewPropagatorFields(t *testing.T) {
+	for _, tc := range []struct {
+		names []string
+		want  []string
+	}{
+		{nil, []string{"traceparent", "tracestate", "baggage"}},
+		{[]string{"b3multi"}, []string{"x-b3-traceid", "x-b3-spanid", "x-b3-sampled", "x-b3-flags"}},
+		{[]string{"jaeger", "jaeger"}, []string{"uber-trace-id"}},
+		{[]string{"none"}, nil},
+	} {
+		fields := newPropagator(tc.names).Fields()
+		if tc.want == nil && len(fields) != 0 {
+			t.Fatalf("%v: expected no fields, got %v", tc.names, fields)
+		}
+		for _, f := range tc.want {
+			if !contains(fields, f) {
+				t.Fatalf("%v: expected field %q in %v", tc.names, f, fields)
+			}
+		}
+	}
+}
+
+func TestApplyConfigDefaultsPropagatorsEnv(t *testing.T) {
+	t.Setenv("OTEL_PROPAGATORS", "tracecontext, b3multi")
+	cfg := &Config{Propagators: []string{"jaeger"}}
+	applyConfigDefaults(cfg)
+	if len(cfg.Propagators) != 2 || cfg.Propagators[0] != "tracecontext" || cfg.Propagators[1] != "b3multi" {
+		t.Fatalf("expected env propagators, got %v", cfg.Propagators)
+	}
+}
+
+func TestCheckEnvOverridesPropagators(t *testing.T) {
+	t.Setenv("OTEL_PROPAGATORS", "tracecontext,xray")
+	err := checkEnvOverrides()
+	if err == nil || !strings.Contains(err.Error(), `OTEL_PROPAGATORS: unsupported propagator "xray"`) {
+		t.Fatalf("expected an OTEL_PROPAGATORS error, got %v", err)
+	}
+
+	t.Setenv("OTEL_PROPAGATORS", "none")
+	if err := checkEnvOverrides(); err != nil {
+		t.Fatalf("expected none to be accepted, got %v", err)
+	}
+}
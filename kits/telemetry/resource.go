@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ResourceGroup is the Fx value group whose []attribute.KeyValue values
+// Module adds to the resource, for attributes only known at runtime (region,
+// instance ID, ...):
+//
+//	fx.Provide(fx.Annotate(
+//	    func(md *cloud.Metadata) []attribute.KeyValue {
+//	        return []attribute.KeyValue{semconv.CloudRegion(md.Region)}
+//	    },
+//	    fx.ResultTags(`group:"otel.resource"`),
+//	))
+//
+// Group attributes win over every other source. Since Fx does not order
+// group values, contributions setting the same key with different values
+// resolve to the greatest value, and a warning is logged.
+const ResourceGroup = "otel.resource"
+
+type providersDeps struct {
+	fx.In
+
+	Ctx      context.Context
+	Cfg      *Config
+	Log      *zap.Logger
+	Resource [][]attribute.KeyValue `group:"otel.resource"`
+	Scrapes  *ScrapeTracker         `optional:"true"`
+}
+
+// provideProviders wraps NewProviders to merge the ResourceGroup attributes
+// into the resource, and to record scrapes of the Prometheus handler for
+// ScrapeReadiness.
+func provideProviders(d providersDeps) (Result, error) {
+	if d.Cfg != nil {
+		d.Cfg.groupAttrs = mergeGroupAttrs(d.Resource, d.Log)
+	}
+	out, err := NewProviders(d.Ctx, d.Cfg, d.Log)
+	if err == nil && d.Scrapes != nil {
+		for i, h := range out.MetricsHandlers {
+			out.MetricsHandlers[i].Handler = d.Scrapes.Wrap(h.Handler)
+		}
+	}
+	return out, err
+}
+
+// mergeGroupAttrs flattens group contributions into one attribute per key,
+// independent of contribution order.
+func mergeGroupAttrs(groups [][]attribute.KeyValue, log *zap.Logger) []attribute.KeyValue {
+	var all []attribute.KeyValue
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Key != all[j].Key {
+			return all[i].Key < all[j].Key
+		}
+		return all[i].Value.Emit() < all[j].Value.Emit()
+	})
+	out := make([]attribute.KeyValue, 0, len(all))
+	for _, kv := range all {
+		if n := len(out); n > 0 && out[n-1].Key == kv.Key {
+			if out[n-1].Value != kv.Value {
+				log.Warn("telemetry: conflicting resource attribute contributions",
+					zap.String("key", string(kv.Key)),
+					zap.String("dropped", out[n-1].Value.Emit()),
+					zap.String("kept", kv.Value.Emit()),
+				)
+			}
+			out[n-1] = kv
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out
+}
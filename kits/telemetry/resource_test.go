@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/fx"
+	fxtest "go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestModuleMergesResourceGroup(t *testing.T) {
+	yaml := "telemetry:\n  set_globals: false\n  resource_attributes:\n    cloud.region: from-config\n    team: payments\n"
+	var tp *sdktrace.TracerProvider
+	app := fxtest.New(t,
+		fx.Provide(context.Background),
+		fx.Provide(zap.NewNop),
+		configkit.Module(configkit.WithEmbeddedBytes([]byte(yaml))),
+		fx.Provide(
+			fx.Annotate(func() []attribute.KeyValue {
+				return []attribute.KeyValue{attribute.String("cloud.region", "eu-west-1")}
+			}, fx.ResultTags(`group:"otel.resource"`)),
+			fx.Annotate(func() []attribute.KeyValue {
+				return []attribute.KeyValue{attribute.String("host.id", "i-123")}
+			}, fx.ResultTags(`group:"otel.resource"`)),
+		),
+		Module(),
+		fx.Populate(&tp),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+	got := map[attribute.Key]string{}
+	for _, kv := range span.(sdktrace.ReadOnlySpan).Resource().Attributes() {
+		got[kv.Key] = kv.Value.Emit()
+	}
+	for k, want := range map[attribute.Key]string{
+		"cloud.region": "eu-west-1", // group wins over config
+		"host.id":      "i-123",
+		"team":         "payments",
+	} {
+		if got[k] != want {
+			t.Fatalf("resource %s = %q, want %q", k, got[k], want)
+		}
+	}
+}
+
+func TestMergeGroupAttrsIsOrderIndependent(t *testing.T) {
+	a := []attribute.KeyValue{attribute.String("region", "eu"), attribute.String("zone", "a")}
+	b := []attribute.KeyValue{attribute.String("region", "us")}
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	ab := mergeGroupAttrs([][]attribute.KeyValue{a, b}, zap.New(core))
+	ba := mergeGroupAttrs([][]attribute.KeyValue{b, a}, zap.NewNop())
+	if len(ab) != 2 || ab[0] != ba[0] || ab[1] != ba[1] {
+		t.Fatalf("expected the same result in any order, got %v and %v", ab, ba)
+	}
+	if ab[0].Value.AsString() != "us" {
+		t.Fatalf("expected the greatest value to win, got %v", ab[0])
+	}
+	if logs.FilterMessage("telemetry: conflicting resource attribute contributions").Len() != 1 {
+		t.Fatalf("expected a conflict warning")
+	}
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingRule overrides the base sampler for spans whose name (and,
+// optionally, attributes) match.
+type SamplingRule struct {
+	// NamePattern matches the span name. "*" matches any run of characters,
+	// e.g. "GET /health*" or "*Kafka*".
+	NamePattern string `yaml:"name_pattern" validate:"required"`
+
+	// Attributes, if set, must all be present on the span at creation time
+	// with exactly these string values.
+	Attributes map[string]string `yaml:"attributes"`
+
+	// Sample is the decision for matching spans: "always_on", "always_off",
+	// or "ratio" (uses Ratio).
+	Sample string `yaml:"sample" validate:"required,oneof=always_on always_off ratio"`
+
+	// Ratio is the fraction of matching traces to sample when Sample is "ratio".
+	Ratio float64 `yaml:"ratio" validate:"gte=0,lte=1"`
+}
+
+// ruleSampler applies the first matching rule and falls back to base when no
+// rule matches. Rules decide regardless of the parent's sampling decision.
+type ruleSampler struct {
+	rules []compiledRule
+	base  sdktrace.Sampler
+}
+
+type compiledRule struct {
+	name    *regexp.Regexp
+	attrs   map[attribute.Key]string
+	sampler sdktrace.Sampler
+}
+
+func newRuleSampler(rules []SamplingRule, base sdktrace.Sampler) (sdktrace.Sampler, error) {
+	s := ruleSampler{base: base}
+	for i, r := range rules {
+		var sampler sdktrace.Sampler
+		switch r.Sample {
+		case "always_on":
+			sampler = sdktrace.AlwaysSample()
+		case "always_off":
+			sampler = sdktrace.NeverSample()
+		case "ratio":
+			sampler = sdktrace.TraceIDRatioBased(r.Ratio)
+		default:
+			return nil, fmt.Errorf("sampling rule %d: unknown sample %q", i, r.Sample)
+		}
+		attrs := make(map[attribute.Key]string, len(r.Attributes))
+		for k, v := range r.Attributes {
+			attrs[attribute.Key(k)] = v
+		}
+		s.rules = append(s.rules, compiledRule{name: globRegexp(r.NamePattern), attrs: attrs, sampler: sampler})
+	}
+	return s, nil
+}
+
+// globRegexp compiles a pattern where "*" matches any run of characters and
+// everything else is literal.
+func globRegexp(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func (s ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, r := range s.rules {
+		if r.matches(p) {
+			return r.sampler.ShouldSample(p)
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{rules=%d,base=%s}", len(s.rules), s.base.Description())
+}
+
+func (r compiledRule) matches(p sdktrace.SamplingParameters) bool {
+	if !r.name.MatchString(p.Name) {
+		return false
+	}
+	for key, want := range r.attrs {
+		found := false
+		for _, kv := range p.Attributes {
+			if kv.Key == key {
+				found = kv.Value.Emit() == want
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
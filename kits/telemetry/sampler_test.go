@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRuleSamplerDecisions(t *testing.T) {
+	rules := []SamplingRule{
+		{NamePattern: "GET /health*", Sample: "always_off"},
+		{NamePattern: "POST /checkout", Sample: "always_on"},
+		{NamePattern: "consume *", Attributes: map[string]string{"messaging.system": "kafka"}, Sample: "ratio", Ratio: 0},
+	}
+	s, err := newRuleSampler(rules, sdktrace.AlwaysSample())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base, err := newRuleSampler(rules, sdktrace.NeverSample())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sampler sdktrace.Sampler
+		span    string
+		attrs   []attribute.KeyValue
+		want    sdktrace.SamplingDecision
+	}{
+		{"health dropped", s, "GET /healthz", nil, sdktrace.Drop},
+		{"critical always sampled", base, "POST /checkout", nil, sdktrace.RecordAndSample},
+		{"attribute rule matches", s, "consume orders", []attribute.KeyValue{attribute.String("messaging.system", "kafka")}, sdktrace.Drop},
+		{"attribute mismatch falls back", s, "consume orders", []attribute.KeyValue{attribute.String("messaging.system", "nats")}, sdktrace.RecordAndSample},
+		{"no match uses base", base, "GET /users", nil, sdktrace.Drop},
+		{"pattern is anchored", s, "xGET /healthz", nil, sdktrace.RecordAndSample},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.sampler.ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: context.Background(),
+				TraceID:       trace.TraceID{1},
+				Name:          tt.span,
+				Attributes:    tt.attrs,
+			})
+			if res.Decision != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, res.Decision)
+			}
+		})
+	}
+}
+
+func TestBuildTracerProviderWithSamplingRules(t *testing.T) {
+	tracing := true
+	cfg := Config{
+		TracingEnabled:  &tracing,
+		TraceSampleRate: 1,
+		SamplingRules:   []SamplingRule{{NamePattern: "GET /health", Sample: "always_off"}},
+	}
+	tp, err := buildTracerProvider(context.Background(), cfg, sdkresource.NewSchemaless(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, health := tp.Tracer("t").Start(context.Background(), "GET /health")
+	_, other := tp.Tracer("t").Start(context.Background(), "GET /users")
+	if health.SpanContext().IsSampled() {
+		t.Fatalf("expected health span to be dropped")
+	}
+	if !other.SpanContext().IsSampled() {
+		t.Fatalf("expected other span to be sampled")
+	}
+}
@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/froppa/stackkit/kits/healthkit"
+	"github.com/froppa/stackkit/kits/httpkit"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/fx"
+)
+
+// MetricsPath is where the Prometheus exporter, or ScrapeReadiness, serves
+// metrics.
+const MetricsPath = "/metrics"
+
+// ScrapeTracker records when a pull-based metrics endpoint (e.g. a Prometheus
+// handler) was last scraped successfully.
+type ScrapeTracker struct {
+	last atomic.Int64 // unix nanoseconds; 0 until the first scrape
+}
+
+// NewScrapeTracker returns a tracker that has seen no scrapes.
+func NewScrapeTracker() *ScrapeTracker { return &ScrapeTracker{} }
+
+// Wrap returns h, recording a scrape whenever it answers with a status
+// below 400.
+func (s *ScrapeTracker) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		if sw.status < http.StatusBadRequest {
+			s.last.Store(time.Now().UnixNano())
+		}
+	})
+}
+
+// Last returns the time of the last successful scrape, or the zero time.
+func (s *ScrapeTracker) Last() time.Time {
+	if n := s.last.Load(); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
+}
+
+// Check returns a readiness check named "metrics_scrape" that passes while
+// the last successful scrape is at most window old.
+func (s *ScrapeTracker) Check(window time.Duration) *healthkit.Check {
+	return &healthkit.Check{
+		Name: "metrics_scrape",
+		Probe: func(context.Context) error {
+			last := s.Last()
+			if last.IsZero() {
+				return errors.New("metrics not scraped yet")
+			}
+			if age := time.Since(last); age > window {
+				return fmt.Errorf("metrics last scraped %s ago", age.Round(time.Second))
+			}
+			return nil
+		},
+	}
+}
+
+// ScrapeReadiness holds readiness until metrics at MetricsPath have been
+// scraped within window, for services that treat being observable as a
+// readiness prerequisite. With `metrics_exporter: prometheus`, Module's own
+// handler is tracked; otherwise ScrapeReadiness serves promhttp.Handler() (the
+// default Prometheus registry) there itself. It contributes to
+// `group:"http.handlers"` and `group:"readiness.checks"`, so it needs
+// httpkit.Module and healthkit's service; the tracker is provided as well.
+// To track another handler, use a ScrapeTracker directly.
+//
+// httpkit's ReadinessGate exempts MetricsPath, so the scrape that makes the
+// service ready is not itself rejected.
+func ScrapeReadiness(window time.Duration) fx.Option {
+	s := NewScrapeTracker()
+	return fx.Options(
+		fx.Supply(s),
+		fx.Provide(
+			fx.Annotate(func(d scrapeDeps) []httpkit.Handler {
+				if d.Cfg != nil && d.Cfg.metricsServed {
+					return nil
+				}
+				return []httpkit.Handler{{Pattern: "GET " + MetricsPath, Handler: s.Wrap(promhttp.Handler())}}
+			}, fx.ResultTags(`group:"http.handlers,flatten"`)),
+			fx.Annotate(func() *healthkit.Check {
+				return s.Check(window)
+			}, fx.ResultTags(`group:"readiness.checks"`)),
+		),
+	)
+}
+
+type scrapeDeps struct {
+	fx.In
+	Cfg *Config `optional:"true"`
+	// MeterProvider orders this after NewProviders, which records in Cfg
+	// whether Module serves MetricsPath.
+	MeterProvider *sdkmetric.MeterProvider `optional:"true"`
+}
+
+// statusWriter captures the response status.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader && code >= 200 {
+		w.status, w.wroteHeader = code, true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
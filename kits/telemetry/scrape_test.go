@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/froppa/stackkit/kits/configkit"
+	"github.com/froppa/stackkit/kits/httpkit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+func TestScrapeTrackerRecordsSuccessfulScrapes(t *testing.T) {
+	s := NewScrapeTracker()
+	status := http.StatusInternalServerError
+	h := s.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	check := s.Check(time.Minute)
+
+	require.ErrorContains(t, check.Probe(context.Background()), "not scraped yet")
+
+	// Failed scrapes do not count.
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	require.True(t, s.Last().IsZero())
+
+	status = http.StatusOK
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	require.False(t, s.Last().IsZero())
+	require.NoError(t, check.Probe(context.Background()))
+
+	// A scrape older than the window fails the check again.
+	s.last.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	require.ErrorContains(t, check.Probe(context.Background()), "last scraped")
+}
+
+func TestScrapeReadinessTracksModuleHandler(t *testing.T) {
+	scrape := func(t *testing.T, yml string, opts ...fx.Option) (string, *ScrapeTracker) {
+		t.Helper()
+		var (
+			s  *ScrapeTracker
+			ln net.Listener
+		)
+		app := fxtest.New(t, append(opts,
+			fx.Provide(context.Background),
+			fx.Provide(zap.NewNop),
+			configkit.Module(configkit.WithEmbeddedBytes([]byte(yml))),
+			httpkit.Module(),
+			ScrapeReadiness(time.Minute),
+			fx.Populate(&s, &ln),
+		)...)
+		app.RequireStart()
+		defer app.RequireStop()
+
+		require.True(t, s.Last().IsZero())
+		resp, err := http.Get("http://" + ln.Addr().String() + MetricsPath)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return string(body), s
+	}
+
+	// Module serves its Prometheus registry; ScrapeReadiness adds no second
+	// /metrics route and tracks Module's.
+	body, s := scrape(t, "http:\n  addr: 127.0.0.1:0\ntelemetry:\n  set_globals: false\n  metrics_exporter: prometheus\n", Module())
+	require.Contains(t, body, "target_info")
+	require.False(t, s.Last().IsZero())
+
+	// Without the exporter it serves the default registry itself.
+	body, s = scrape(t, "http:\n  addr: 127.0.0.1:0\n")
+	require.True(t, strings.Contains(body, "go_goroutines"), body)
+	require.False(t, s.Last().IsZero())
+}
@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// dialShared returns the connection both OTLP exporters share when
+// SharedConnection is set, or nil when each exporter should dial its own.
+// Signals sent to different endpoints never share.
+func dialShared(cfg Config) (*grpc.ClientConn, error) {
+	// Only OTLP over gRPC to a single endpoint has a connection to share.
+	endpoint := traceEndpoint(cfg)
+	otlpGRPC := cfg.Exporter != ExporterStdout && exporting(cfg, endpoint) &&
+		cfg.Protocol != ProtocolHTTPProtobuf && metricEndpoint(cfg) == endpoint
+	if !cfg.SharedConnection || !otlpGRPC || (!*cfg.TracingEnabled && !*cfg.MetricsEnabled) {
+		return nil, nil
+	}
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("otlp shared connection: %w", err)
+	}
+	return conn, nil
+}